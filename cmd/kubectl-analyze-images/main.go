@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ronaknnathani/kubectl-analyze-images/pkg/plugin"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
 )
 
 var (
@@ -32,20 +37,182 @@ It extracts image sizes from node status and generates reports with performance
 			if err := o.Validate(); err != nil {
 				return err
 			}
-			return o.Run(context.Background())
+			// Policy violations are not usage errors
+			cmd.SilenceUsage = true
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return o.Run(ctx)
 		},
 	}
 
 	// Bind flags directly to AnalyzeOptions fields
-	rootCmd.Flags().StringVarP(&o.Namespace, "namespace", "n", "", "Target namespace (default: all namespaces)")
+	rootCmd.Flags().StringSliceVarP(&o.Namespaces, "namespace", "n", nil, "Target namespaces, comma-separated or repeated (default: all namespaces)")
+	rootCmd.Flags().StringSliceVar(&o.ExcludeNamespaces, "exclude-namespace", nil, "Namespaces to leave out, comma-separated or repeated")
+	rootCmd.Flags().StringVar(&o.NamespaceSelector, "namespace-selector", "", "Label selector for namespaces (e.g. team=payments)")
 	rootCmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", "", "Label selector for pods")
-	rootCmd.Flags().StringVarP(&o.OutputFormat, "output", "o", "table", "Output format: table, json")
+	rootCmd.Flags().StringVar(&o.FieldSelector, "field-selector", "", "Field selector for pods (e.g. status.phase!=Succeeded,spec.nodeName=node1)")
+	rootCmd.Flags().StringSliceVar(&o.PodPhases, "pod-phase", nil, "Only analyze pods in these phases: Pending, Running, Succeeded, Failed, Unknown (default: all)")
+	rootCmd.Flags().StringSliceVar(&o.Nodes, "node", nil, "Only analyze these nodes and the pods scheduled on them, comma-separated or repeated")
+	rootCmd.Flags().StringVar(&o.NodeSelector, "node-selector", "", "Label selector for nodes; only matching nodes and the pods scheduled on them are analyzed (e.g. pool=gpu)")
+	rootCmd.Flags().StringVarP(&o.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, csv, tsv, html, markdown, go-template=..., go-template-file=..., jsonpath=...")
+	rootCmd.Flags().StringSliceVar(&o.Columns, "columns", nil, "Columns for csv and tsv output: name, registry, repository, tag, digest, size, human-size, node-count, cluster-size, inaccessible, namespaces, container-types")
 	rootCmd.Flags().BoolVar(&o.NoColor, "no-color", false, "Disable colored output (default: false)")
 	rootCmd.Flags().IntVar(&o.TopImages, "top-images", 25, "Number of top images to show in the report (default: 25)")
+	rootCmd.Flags().StringVar(&o.SortBy, "sort-by", "size", "Sort top images by: size, cluster-size (size × node count)")
+	rootCmd.Flags().StringVar(&o.GroupBy, "group-by", "", "Attribute image sizes to a grouping: namespace, workload, registry, repository, container-type")
+	rootCmd.Flags().StringSliceVar(&o.ContainerTypes, "container-type", nil, "Only analyze images of these container types: regular, init, sidecar, ephemeral (default: all)")
+	rootCmd.Flags().BoolVar(&o.TagHygiene, "tag-hygiene", false, "Report images using :latest or no tag, images not pinned by digest, and repositories running several versions (default: false)")
+	rootCmd.Flags().BoolVar(&o.TagDrift, "tag-drift", false, "Report tags that resolve to different digests across nodes and pods (default: false)")
 	rootCmd.Flags().StringVar(&o.KubeContext, "context", "", "Kubernetes context to use (default: current context)")
+	rootCmd.Flags().StringVar(&o.PolicyFile, "policy", "", "YAML or JSON policy file; violations exit with code 3")
+	rootCmd.Flags().StringVar(&o.MaxImageSize, "max-image-size", "", "Fail if any image is larger than this size (e.g. 2Gi)")
+	rootCmd.Flags().StringVar(&o.NamespaceBudget, "namespace-budget", "", "Fail if any namespace uses more image bytes than this size (e.g. 20Gi)")
+	rootCmd.Flags().BoolVar(&o.DisallowLatestTag, "disallow-latest", false, "Fail if any image uses the :latest tag or has no tag (default: false)")
+	rootCmd.Flags().StringSliceVar(&o.AllowedRegistries, "allowed-registries", nil, "Fail if any image comes from a registry not in this list")
+	rootCmd.Flags().BoolVarP(&o.Watch, "watch", "w", false, "Watch pods and nodes and re-render the report (or stream JSON lines of changes with -o json) as they change")
+	rootCmd.Flags().DurationVar(&o.WatchInterval, "watch-interval", 2*time.Second, "Minimum time between updates in watch mode")
+	rootCmd.Flags().Int64Var(&o.PodPageSize, "pod-page-size", types.DefaultPodPageSize, "Number of pods to list per request")
+	rootCmd.Flags().Int64Var(&o.NodePageSize, "node-page-size", types.DefaultNodePageSize, "Number of nodes to list per request")
+	rootCmd.Flags().BoolVar(&o.Unpaginated, "unpaginated", false, "List all pods and nodes in one request each from the API server cache instead of paginating (default: false)")
+	rootCmd.Flags().StringSliceVar(&o.FromFiles, "from-file", nil, "Analyze a snapshot or 'kubectl get pods,nodes -o json' output instead of the cluster (repeatable)")
+
+	no := &plugin.NodesOptions{}
+
+	nodesCmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "Report the image disk footprint of each node",
+		Long: `Report, per node, the number of cached images, total image bytes, bytes used by
+images that no pod scheduled to the node references, and the node's ephemeral-storage
+capacity and allocatable for comparison against image GC thresholds.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := no.Complete(); err != nil {
+				return err
+			}
+			if err := no.Validate(); err != nil {
+				return err
+			}
+			return no.Run(context.Background())
+		},
+	}
+
+	nodesCmd.Flags().StringVarP(&no.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, go-template=..., go-template-file=..., jsonpath=...")
+	nodesCmd.Flags().BoolVar(&no.NoColor, "no-color", false, "Disable colored output (default: false)")
+	nodesCmd.Flags().StringVar(&no.KubeContext, "context", "", "Kubernetes context to use (default: current context)")
+	nodesCmd.Flags().StringSliceVar(&no.FromFiles, "from-file", nil, "Analyze a snapshot or 'kubectl get pods,nodes -o json' output instead of the cluster (repeatable)")
+
+	uo := &plugin.UnusedOptions{}
+
+	unusedCmd := &cobra.Command{
+		Use:   "unused",
+		Short: "Find images cached on nodes that no pod references",
+		Long: `Find images present in node status that are not referenced by any pod scheduled to
+that node, and report the reclaimable bytes per node and cluster-wide. With --cluster-wide,
+an image only counts as unused if no pod anywhere in the cluster references it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := uo.Complete(); err != nil {
+				return err
+			}
+			if err := uo.Validate(); err != nil {
+				return err
+			}
+			return uo.Run(context.Background())
+		},
+	}
+
+	unusedCmd.Flags().StringVarP(&uo.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, go-template=..., go-template-file=..., jsonpath=...")
+	unusedCmd.Flags().BoolVar(&uo.NoColor, "no-color", false, "Disable colored output (default: false)")
+	unusedCmd.Flags().IntVar(&uo.TopImages, "top-images", 25, "Number of largest unused images to show in the report (default: 25)")
+	unusedCmd.Flags().StringVar(&uo.KubeContext, "context", "", "Kubernetes context to use (default: current context)")
+	unusedCmd.Flags().StringSliceVar(&uo.FromFiles, "from-file", nil, "Analyze a snapshot or 'kubectl get pods,nodes -o json' output instead of the cluster (repeatable)")
+	unusedCmd.Flags().BoolVar(&uo.ClusterWide, "cluster-wide", false, "Only report images not referenced by any pod in the cluster (default: false)")
+
+	so := &plugin.SnapshotOptions{}
+
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Export the pods and nodes needed for offline analysis",
+		Long: `Write the pods, nodes, replica sets and jobs the analysis needs to a single
+gzip-compressed JSON file. Analyze it later without cluster access with --from-file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := so.Complete(); err != nil {
+				return err
+			}
+			if err := so.Validate(); err != nil {
+				return err
+			}
+			return so.Run(context.Background())
+		},
+	}
+
+	snapshotCmd.Flags().StringSliceVarP(&so.Namespaces, "namespace", "n", nil, "Namespaces of pods to export, comma-separated or repeated (default: all namespaces)")
+	snapshotCmd.Flags().StringVarP(&so.OutputFile, "file", "f", "snapshot.json.gz", "File to write the snapshot to, or - for stdout")
+	snapshotCmd.Flags().StringVar(&so.KubeContext, "context", "", "Kubernetes context to use (default: current context)")
+
+	do := &plugin.DiffOptions{}
+
+	diffCmd := &cobra.Command{
+		Use:   "diff OLD NEW",
+		Short: "Compare two JSON reports or snapshots",
+		Long: `Compare two analyses and show added and removed images, size changes for the
+same repository across tags, and the net total and unique size change per namespace and
+registry. Each argument is a report written with -o json or a snapshot file; snapshots
+are analyzed with images attributed to namespaces.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := do.Complete(args); err != nil {
+				return err
+			}
+			if err := do.Validate(); err != nil {
+				return err
+			}
+			return do.Run(context.Background())
+		},
+	}
+
+	diffCmd.Flags().StringVarP(&do.OutputFormat, "output", "o", "table", "Output format: table, json, yaml, go-template=..., go-template-file=..., jsonpath=...")
+	diffCmd.Flags().BoolVar(&do.NoColor, "no-color", false, "Disable colored output (default: false)")
+	diffCmd.Flags().IntVar(&do.TopImages, "top-images", 25, "Number of largest added and removed images to show (default: 25)")
+
+	svo := &plugin.ServeOptions{}
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Export analysis results as Prometheus metrics",
+		Long: `Run the analysis on an interval and serve the results at /metrics in the
+Prometheus text format: per-image size, per-namespace and per-node image bytes, the
+inaccessible image count and analysis timings. /healthz reports ready once an
+analysis has succeeded.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := svo.Complete(); err != nil {
+				return err
+			}
+			if err := svo.Validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return svo.Run(ctx)
+		},
+	}
+
+	serveCmd.Flags().StringSliceVarP(&svo.Namespaces, "namespace", "n", nil, "Target namespaces, comma-separated or repeated (default: all namespaces)")
+	serveCmd.Flags().StringSliceVar(&svo.ExcludeNamespaces, "exclude-namespace", nil, "Namespaces to leave out, comma-separated or repeated")
+	serveCmd.Flags().StringVar(&svo.NamespaceSelector, "namespace-selector", "", "Label selector for namespaces, resolved on every analysis (e.g. team=payments)")
+	serveCmd.Flags().StringVarP(&svo.LabelSelector, "selector", "l", "", "Label selector for pods")
+	serveCmd.Flags().StringVar(&svo.KubeContext, "context", "", "Kubernetes context to use (default: current context)")
+	serveCmd.Flags().StringSliceVar(&svo.FromFiles, "from-file", nil, "Analyze a snapshot or 'kubectl get pods,nodes -o json' output instead of the cluster (repeatable)")
+	serveCmd.Flags().StringVar(&svo.ListenAddress, "listen-address", ":8080", "Address to serve /metrics and /healthz on")
+	serveCmd.Flags().DurationVar(&svo.Interval, "interval", 5*time.Minute, "Time between analyses")
+
+	rootCmd.AddCommand(nodesCmd, unusedCmd, snapshotCmd, diffCmd, serveCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var violationErr *plugin.PolicyViolationError
+		if errors.As(err, &violationErr) {
+			os.Exit(plugin.ExitCodePolicyViolation)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// NodeAnalyzer coordinates per-node image footprint analysis
+type NodeAnalyzer struct {
+	clusterClient *cluster.Client
+}
+
+// NewNodeAnalyzer creates a new node analyzer
+func NewNodeAnalyzer(clusterClient *cluster.Client) *NodeAnalyzer {
+	return &NodeAnalyzer{
+		clusterClient: clusterClient,
+	}
+}
+
+// AnalyzeNodes reports the image disk footprint of every node, including bytes
+// used by images that no pod scheduled to the node references
+func (na *NodeAnalyzer) AnalyzeNodes(ctx context.Context) (*types.NodeAnalysis, error) {
+	overallStart := time.Now()
+
+	pods, nodes, perfMetrics, err := na.listPodsAndNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	analysisStart := time.Now()
+	usage := types.AnalyzeNodeUsage(nodes, pods)
+	perfMetrics.ImageAnalysisTime = time.Since(analysisStart)
+	perfMetrics.TotalTime = time.Since(overallStart)
+
+	for _, node := range usage {
+		perfMetrics.ImagesProcessed += node.ImageCount
+	}
+
+	return &types.NodeAnalysis{
+		Nodes:       usage,
+		Performance: perfMetrics,
+	}, nil
+}
+
+// FindUnusedImages reports images cached on each node that no pod scheduled to the
+// node references. With clusterWide, images referenced by any pod in the cluster
+// are treated as used.
+func (na *NodeAnalyzer) FindUnusedImages(ctx context.Context, clusterWide bool) (*types.UnusedImageAnalysis, error) {
+	overallStart := time.Now()
+
+	pods, nodes, perfMetrics, err := na.listPodsAndNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	analysisStart := time.Now()
+	unused := types.FindUnusedImages(nodes, pods, clusterWide)
+	perfMetrics.ImageAnalysisTime = time.Since(analysisStart)
+	perfMetrics.TotalTime = time.Since(overallStart)
+
+	for _, node := range nodes {
+		perfMetrics.ImagesProcessed += len(node.Images)
+	}
+
+	return &types.UnusedImageAnalysis{
+		Nodes:       unused,
+		ClusterWide: clusterWide,
+		Performance: perfMetrics,
+	}, nil
+}
+
+// listPodsAndNodes lists all pods and nodes in the cluster concurrently. All pods
+// are needed to determine which cached images are still referenced.
+func (na *NodeAnalyzer) listPodsAndNodes(ctx context.Context) ([]types.Pod, []types.Node, *types.PerformanceMetrics, error) {
+	var pods []types.Pod
+	var nodes []types.Node
+	var podMetrics, nodeMetrics *types.PerformanceMetrics
+
+	queryStart := time.Now()
+	err := cluster.RunQueries(ctx,
+		func(ctx context.Context) error {
+			var err error
+			pods, podMetrics, err = na.clusterClient.ListPods(ctx, "", "")
+			if err != nil {
+				return fmt.Errorf("failed to list pods: %w", err)
+			}
+			return nil
+		},
+		func(ctx context.Context) error {
+			var err error
+			nodes, nodeMetrics, err = na.clusterClient.ListNodes(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list nodes: %w", err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	perfMetrics := &types.PerformanceMetrics{
+		PodQueryTime:    podMetrics.PodQueryTime,
+		NodeQueryTime:   nodeMetrics.NodeQueryTime,
+		QueryTime:       time.Since(queryStart),
+		SummedQueryTime: podMetrics.SummedQueryTime + nodeMetrics.NodeQueryTime,
+	}
+
+	return pods, nodes, perfMetrics, nil
+}
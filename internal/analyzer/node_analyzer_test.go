@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+)
+
+func TestNodeAnalyzer_AnalyzeNodes(t *testing.T) {
+	ctx := context.Background()
+
+	pod1 := createTestPod("pod1", "default", "nginx:1.21")
+	pod1.Spec.NodeName = "node1"
+	pod2 := createTestPod("pod2", "kube-system", "coredns:1.9")
+	pod2.Spec.NodeName = "node2"
+
+	node1 := createTestNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"old:v1":     300000000,
+	})
+	node1.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceEphemeralStorage: resource.MustParse("1G"),
+	}
+	node2 := createTestNode("node2", map[string]int64{
+		"coredns:1.9": 40000000,
+	})
+
+	fakeK8s := kubernetes.NewFakeClient(pod1, pod2, node1, node2)
+	clusterClient := cluster.NewClient(fakeK8s)
+	nodeAnalyzer := NewNodeAnalyzer(clusterClient)
+
+	result, err := nodeAnalyzer.AnalyzeNodes(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, result.Performance)
+	assert.Equal(t, 3, result.Performance.ImagesProcessed)
+	require.Len(t, result.Nodes, 2)
+
+	assert.Equal(t, "node1", result.Nodes[0].Name)
+	assert.Equal(t, 2, result.Nodes[0].ImageCount)
+	assert.Equal(t, int64(400000000), result.Nodes[0].TotalBytes)
+	assert.Equal(t, int64(300000000), result.Nodes[0].UnreferencedBytes)
+	assert.Equal(t, int64(1000000000), result.Nodes[0].EphemeralStorageAllocatable)
+
+	assert.Equal(t, "node2", result.Nodes[1].Name)
+	assert.Equal(t, int64(0), result.Nodes[1].UnreferencedBytes)
+}
+
+func TestNodeAnalyzer_FindUnusedImages(t *testing.T) {
+	ctx := context.Background()
+
+	pod1 := createTestPod("pod1", "default", "nginx:1.21")
+	pod1.Spec.NodeName = "node1"
+	pod2 := createTestPod("pod2", "default", "redis:6.2")
+	pod2.Spec.NodeName = "node2"
+
+	node1 := createTestNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"redis:6.2":  50000000,
+	})
+	node2 := createTestNode("node2", map[string]int64{
+		"redis:6.2": 50000000,
+	})
+
+	fakeK8s := kubernetes.NewFakeClient(pod1, pod2, node1, node2)
+	nodeAnalyzer := NewNodeAnalyzer(cluster.NewClient(fakeK8s))
+
+	result, err := nodeAnalyzer.FindUnusedImages(ctx, false)
+	require.NoError(t, err)
+	assert.False(t, result.ClusterWide)
+	assert.Equal(t, 3, result.Performance.ImagesProcessed)
+	require.Len(t, result.Nodes, 1)
+	assert.Equal(t, "node1", result.Nodes[0].Node)
+	assert.Equal(t, int64(50000000), result.ReclaimableBytes())
+
+	result, err = nodeAnalyzer.FindUnusedImages(ctx, true)
+	require.NoError(t, err)
+	assert.True(t, result.ClusterWide)
+	assert.Empty(t, result.Nodes)
+}
@@ -3,14 +3,12 @@ package analyzer
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/briandowns/spinner"
 
 	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
 	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
-	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
 )
 
 // PodAnalyzer coordinates pod and image analysis
@@ -19,62 +17,148 @@ type PodAnalyzer struct {
 	config        *types.AnalysisConfig
 }
 
-// NewPodAnalyzer creates a new pod analyzer with custom configuration
+// NewPodAnalyzer creates a new pod analyzer with custom configuration. The page
+// sizes and pod and node filters of the configuration are applied to a copy of
+// the client, so the caller's client is left unchanged.
 func NewPodAnalyzer(clusterClient *cluster.Client, config *types.AnalysisConfig) *PodAnalyzer {
+	client := clusterClient.Copy()
+	client.SetPageSizes(config.PodPageSize, config.NodePageSize)
+	client.SetPodFilter(config.PodFilter)
+	client.SetNodeFilter(config.NodeFilter)
 	return &PodAnalyzer{
-		clusterClient: clusterClient,
+		clusterClient: client,
 		config:        config,
 	}
 }
 
-// AnalyzePods analyzes container images from pods
+// AnalyzePods analyzes container images from pods in the given namespace (empty for all)
 func (pa *PodAnalyzer) AnalyzePods(ctx context.Context, namespace, labelSelector string) (*types.ImageAnalysis, error) {
+	var filter types.NamespaceFilter
+	if namespace != "" {
+		filter.Include = []string{namespace}
+	}
+	return pa.AnalyzePodsIn(ctx, filter, labelSelector)
+}
+
+// AnalyzePodsIn analyzes container images from pods in the namespaces selected by
+// the filter. Pods, workload owners and nodes are queried concurrently, and the
+// pods of several namespaces are listed concurrently.
+func (pa *PodAnalyzer) AnalyzePodsIn(ctx context.Context, filter types.NamespaceFilter, labelSelector string) (*types.ImageAnalysis, error) {
 	overallStart := time.Now()
 
+	// Resolve the namespace selector before listing pods
+	if filter.Selector != "" || len(filter.Exclude) > 0 {
+		var err error
+		if filter, err = pa.clusterClient.ResolveNamespaces(ctx, filter); err != nil {
+			return nil, fmt.Errorf("failed to resolve namespaces: %w", err)
+		}
+	}
+
+	// Workload owners are only listed per namespace for a single namespace
+	ownerNamespace := ""
+	if len(filter.Include) == 1 {
+		ownerNamespace = filter.Include[0]
+	}
+
+	var podImages *types.PodImages
 	var pods []types.Pod
-	var perfMetrics *types.PerformanceMetrics
-	var err error
+	var owners map[types.Workload]types.Workload
+	var imageIndex *types.NodeImageIndex
+	perfMetrics := &types.PerformanceMetrics{}
+
+	var queries []func(ctx context.Context) error
+
+	// Only query pods if namespaces or a label selector are specified, or if
+	// image sizes need to be attributed to pods or containers, or their image
+	// references checked. Registry and repository grouping only need the images themselves.
+	attributesNamespaces := pa.config.GroupBy == types.GroupByNamespace || pa.config.NamespaceUsage
+	groupsPods := attributesNamespaces || pa.config.GroupBy == types.GroupByWorkload ||
+		pa.config.GroupBy == types.GroupByContainerType
+	filtersPods := labelSelector != "" || pa.config.PodFilter.FieldSelector != "" || len(pa.config.PodFilter.Phases) > 0
+	if !filter.IsAll() || filtersPods || len(pa.config.ContainerTypes) > 0 || groupsPods ||
+		pa.config.ListPods || pa.config.TagHygiene || pa.config.TagDrift {
+		queries = append(queries, func(ctx context.Context) error {
+			// Fold pods into their image references as pages arrive. Individual pods
+			// are only kept when grouping by workload or checking tag drift.
+			podImages = types.NewPodImages()
+			podMetrics, err := pa.clusterClient.EachPod(ctx, filter.Include, labelSelector, func(pod types.Pod) {
+				// Excluded namespaces are dropped here when listing all namespaces
+				if !filter.Matches(pod.Namespace) {
+					return
+				}
+				if len(pa.config.ContainerTypes) > 0 {
+					pod = pod.WithContainerTypes(pa.config.ContainerTypes)
+				}
+				podImages.Add(pod)
+				if pa.config.GroupBy == types.GroupByWorkload || pa.config.TagDrift {
+					pods = append(pods, pod)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list pods: %w", err)
+			}
+			perfMetrics.PodQueryTime = podMetrics.PodQueryTime
+			perfMetrics.SummedQueryTime += podMetrics.SummedQueryTime
+			return nil
+		})
+	}
 
-	// Only query pods if namespace or label selector is specified
-	if namespace != "" || labelSelector != "" {
-		pods, perfMetrics, err = pa.clusterClient.ListPods(ctx, namespace, labelSelector)
+	// Resolve intermediate controllers (ReplicaSets, Jobs) when grouping by workload
+	var ownerMetrics *types.PerformanceMetrics
+	if pa.config.GroupBy == types.GroupByWorkload {
+		queries = append(queries, func(ctx context.Context) error {
+			var err error
+			owners, ownerMetrics, err = pa.clusterClient.GetWorkloadOwners(ctx, ownerNamespace)
+			if err != nil {
+				return fmt.Errorf("failed to resolve workload owners: %w", err)
+			}
+			return nil
+		})
+	}
+
+	// Get image names, digests and sizes from node status
+	var nodeMetrics *types.PerformanceMetrics
+	queries = append(queries, func(ctx context.Context) error {
+		var err error
+		imageIndex, nodeMetrics, err = pa.clusterClient.GetImageIndexFromNodes(ctx, pa.config.TagDrift)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list pods: %w", err)
+			return fmt.Errorf("failed to get image sizes from nodes: %w", err)
 		}
-	} else {
-		// No filters specified, use all images from nodes
-		perfMetrics = &types.PerformanceMetrics{}
-	}
+		return nil
+	})
 
-	// Get image sizes from node status
-	imageSizes, nodeMetrics, err := pa.clusterClient.GetImageSizesFromNodes(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get image sizes from nodes: %w", err)
+	queryStart := time.Now()
+	if err := cluster.RunQueries(ctx, queries...); err != nil {
+		return nil, err
 	}
 
 	// Merge performance metrics
-	if perfMetrics == nil {
-		perfMetrics = nodeMetrics
-	} else {
-		perfMetrics.NodeQueryTime = nodeMetrics.NodeQueryTime
+	perfMetrics.QueryTime = time.Since(queryStart)
+	perfMetrics.NodeQueryTime = nodeMetrics.NodeQueryTime
+	perfMetrics.SummedQueryTime += nodeMetrics.NodeQueryTime
+	if ownerMetrics != nil {
+		perfMetrics.OwnerQueryTime = ownerMetrics.OwnerQueryTime
+		perfMetrics.SummedQueryTime += ownerMetrics.OwnerQueryTime
 	}
 
 	// Start timing image analysis
 	imageAnalysisStart := time.Now()
 
 	// Create spinner for image analysis
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(pa.clusterClient.ProgressOutput()))
 	_ = s.Color("cyan")
 
 	// Determine which images to analyze
 	var imagesToAnalyze map[string]bool
-	if len(pods) > 0 {
-		// Use images from pods if we queried pods
-		imagesToAnalyze = pa.clusterClient.GetUniqueImages(pods)
+	var imageIDs map[string]string
+	if podImages != nil {
+		// Use images from pods if we queried pods, even if none matched the filters
+		imagesToAnalyze = podImages.Images
+		imageIDs = podImages.ImageIDs
 	} else {
-		// Use all images from nodes if no pod filters
+		// Use all images from nodes if pods were not queried
 		imagesToAnalyze = make(map[string]bool)
-		for imageName := range imageSizes {
+		for imageName := range imageIndex.Sizes {
 			imagesToAnalyze[imageName] = true
 		}
 	}
@@ -83,43 +167,53 @@ func (pa *PodAnalyzer) AnalyzePods(ctx context.Context, namespace, labelSelector
 	s.Suffix = fmt.Sprintf(" Analyzing %d images...", len(imagesToAnalyze))
 	s.Start()
 
-	// Create images from node data
+	// Create images from node data. Pod references that resolve to the same
+	// node image (e.g. "nginx" and "docker.io/library/nginx:latest") are
+	// reported once under the node's canonical name.
 	images := make([]types.Image, 0, len(imagesToAnalyze))
-	var totalSize int64
+	resolved := make(map[string]string)
+	seen := make(map[string]bool)
+	var totalSize, clusterSize int64
 	var processedCount int
 
-	for imageName := range imagesToAnalyze {
-		size, exists := imageSizes[imageName]
+	for imageRef := range imagesToAnalyze {
+		imageName, size, exists := imageIndex.Lookup(imageRef, imageIDs[imageRef])
 		if !exists {
 			// Image not found in node status, mark as inaccessible
-			registry, tag := util.ExtractRegistryAndTag(imageName)
-			images = append(images, types.Image{
-				Name:         imageName,
-				Size:         0,
-				Registry:     registry,
-				Tag:          tag,
-				Inaccessible: true,
-			})
+			images = append(images, *types.NewInaccessibleImage(imageRef))
+			resolved[imageRef] = imageRef
 		} else {
-			// Image found, create entry with size
-			registry, tag := util.ExtractRegistryAndTag(imageName)
-			images = append(images, types.Image{
-				Name:         imageName,
-				Size:         size,
-				Registry:     registry,
-				Tag:          tag,
-				Inaccessible: false,
-			})
+			resolved[imageRef] = imageName
+			if seen[imageName] {
+				continue
+			}
+			seen[imageName] = true
+
+			// Image found, create entry with size and the nodes holding it
+			img := types.NewImage(imageName, size)
+			img.SetNodes(imageIndex.Nodes[imageName])
+			images = append(images, *img)
 			totalSize += size
+			clusterSize += img.ClusterSize
 		}
 		processedCount++
 	}
 
+	// Record the namespaces and container types using each image
+	if podImages != nil {
+		namespaces := podImages.ImageNamespaces(resolved)
+		containerTypes := podImages.ImageContainerTypes(resolved)
+		for i := range images {
+			images[i].Namespaces = namespaces[images[i].Name]
+			images[i].ContainerTypes = containerTypes[images[i].Name]
+		}
+	}
+
 	s.Stop()
 	imageAnalysisTime := time.Since(imageAnalysisStart)
 
 	// Show completion message
-	fmt.Fprintf(os.Stderr, "✓ Completed analyzing %d images (time: %v)\n", processedCount, imageAnalysisTime)
+	fmt.Fprintf(pa.clusterClient.ProgressOutput(), "✓ Completed analyzing %d images (time: %v)\n", processedCount, imageAnalysisTime)
 
 	// Update performance metrics
 	perfMetrics.ImageAnalysisTime = imageAnalysisTime
@@ -130,8 +224,30 @@ func (pa *PodAnalyzer) AnalyzePods(ctx context.Context, namespace, labelSelector
 	analysis := &types.ImageAnalysis{
 		Images:      images,
 		TotalSize:   totalSize,
-		UniqueSize:  totalSize, // No deduplication in this approach
+		UniqueSize:  totalSize, // Images are deduplicated by name above
+		ClusterSize: clusterSize,
 		Performance: perfMetrics,
+		PodImages:   podImages,
+	}
+
+	if attributesNamespaces {
+		analysis.Namespaces = podImages.AttributeNamespaces(resolved, imageIndex.Sizes)
+	}
+	switch pa.config.GroupBy {
+	case types.GroupByWorkload:
+		analysis.Workloads = types.AttributeWorkloads(pods, owners, resolved, imageIndex.Sizes)
+	case types.GroupByRegistry:
+		analysis.Registries = types.AttributeRegistries(images)
+	case types.GroupByRepository:
+		analysis.Repositories = types.AttributeRepositories(images)
+	case types.GroupByContainerType:
+		analysis.ContainerTypes = types.AttributeContainerTypes(images)
+	}
+	if pa.config.TagHygiene {
+		analysis.TagHygiene = podImages.TagHygiene()
+	}
+	if pa.config.TagDrift {
+		analysis.TagDrift = types.DetectTagDrift(pods, imageIndex)
 	}
 
 	return analysis, nil
@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+	"strconv"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// heapObjectsMetric is the runtime metric holding the bytes of live and
+// unswept heap objects
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// syntheticClient generates pods and nodes on demand, honoring the limit and
+// continue token of each list call like the API server does, so a large cluster
+// can be simulated without holding it in memory. It records the peak heap size
+// seen before and after each list call.
+type syntheticClient struct {
+	pods, nodes, images int
+	peakHeap            uint64
+	sample              []metrics.Sample
+}
+
+func newSyntheticClient(pods, nodes, images int) *syntheticClient {
+	return &syntheticClient{
+		pods:   pods,
+		nodes:  nodes,
+		images: images,
+		sample: []metrics.Sample{{Name: heapObjectsMetric}},
+	}
+}
+
+// recordHeap samples the heap size and keeps the largest value seen
+func (c *syntheticClient) recordHeap() {
+	metrics.Read(c.sample)
+	if heap := c.sample[0].Value.Uint64(); heap > c.peakHeap {
+		c.peakHeap = heap
+	}
+}
+
+// pageBounds returns the range of items selected by the options and the
+// continue token of the next page
+func pageBounds(total int, opts metav1.ListOptions) (int, int, string) {
+	start, _ := strconv.Atoi(opts.Continue)
+	if opts.Limit == 0 || start+int(opts.Limit) >= total {
+		return start, total, ""
+	}
+	end := start + int(opts.Limit)
+	return start, end, strconv.Itoa(end)
+}
+
+func (c *syntheticClient) imageName(i int) string {
+	return fmt.Sprintf("registry.example.com/team-%d/app-%d:v1", i%20, i)
+}
+
+func (c *syntheticClient) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	c.recordHeap()
+	start, end, next := pageBounds(c.pods, opts)
+	list := &corev1.PodList{ListMeta: metav1.ListMeta{Continue: next}}
+	list.Items = make([]corev1.Pod, 0, end-start)
+	for i := start; i < end; i++ {
+		list.Items = append(list.Items, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("pod-%d", i),
+				Namespace: fmt.Sprintf("namespace-%d", i%50),
+				Labels:    map[string]string{"app": fmt.Sprintf("app-%d", i%c.images)},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: fmt.Sprintf("node-%d", i%c.nodes),
+				Containers: []corev1.Container{
+					{Name: "app", Image: c.imageName(i % c.images)},
+					{Name: "sidecar", Image: c.imageName((i + 1) % c.images)},
+				},
+			},
+		})
+	}
+	c.recordHeap()
+	return list, nil
+}
+
+func (c *syntheticClient) ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	c.recordHeap()
+	start, end, next := pageBounds(c.nodes, opts)
+	list := &corev1.NodeList{ListMeta: metav1.ListMeta{Continue: next}}
+	list.Items = make([]corev1.Node, 0, end-start)
+	for i := start; i < end; i++ {
+		// Every node caches every image, as on a busy cluster
+		nodeImages := make([]corev1.ContainerImage, c.images)
+		for j := range nodeImages {
+			nodeImages[j] = corev1.ContainerImage{
+				Names:     []string{c.imageName(j)},
+				SizeBytes: int64(j+1) * 1000000,
+			}
+		}
+		list.Items = append(list.Items, corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)},
+			Status:     corev1.NodeStatus{Images: nodeImages},
+		})
+	}
+	c.recordHeap()
+	return list, nil
+}
+
+func (c *syntheticClient) ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error) {
+	return &appsv1.ReplicaSetList{}, nil
+}
+
+func (c *syntheticClient) ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (*batchv1.JobList, error) {
+	return &batchv1.JobList{}, nil
+}
+
+func (c *syntheticClient) ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	return &corev1.NamespaceList{}, nil
+}
+
+var errSyntheticWatch = errors.New("watch is not supported by the synthetic client")
+
+func (c *syntheticClient) WatchPods(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, errSyntheticWatch
+}
+
+func (c *syntheticClient) WatchNodes(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, errSyntheticWatch
+}
+
+func (c *syntheticClient) WatchReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, errSyntheticWatch
+}
+
+func (c *syntheticClient) WatchJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, errSyntheticWatch
+}
+
+// BenchmarkPodAnalyzer_LargeCluster analyzes a synthetic cluster of 50k pods and
+// 2k nodes. The peak-heap-bytes metric shows the memory held while listing:
+// paginated lists keep it bounded by the page size, while page size 0 holds the
+// whole cluster in a single response.
+func BenchmarkPodAnalyzer_LargeCluster(b *testing.B) {
+	benchmarks := []struct {
+		name         string
+		podPageSize  int64
+		nodePageSize int64
+	}{
+		{name: "paginated", podPageSize: types.DefaultPodPageSize, nodePageSize: types.DefaultNodePageSize},
+		{name: "unpaginated", podPageSize: 0, nodePageSize: 0},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			var peakHeap uint64
+			for i := 0; i < b.N; i++ {
+				k8sClient := newSyntheticClient(50000, 2000, 200)
+				clusterClient := cluster.NewClient(k8sClient)
+
+				config := types.DefaultAnalysisConfig()
+				config.GroupBy = types.GroupByNamespace
+				config.PodPageSize = bm.podPageSize
+				config.NodePageSize = bm.nodePageSize
+
+				// Start each run from a collected heap so the peaks are comparable
+				runtime.GC()
+				k8sClient.recordHeap()
+				baseline := k8sClient.peakHeap
+				k8sClient.peakHeap = 0
+
+				analysis, err := NewPodAnalyzer(clusterClient, config).AnalyzePods(context.Background(), "", "")
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(analysis.Images) != 200 {
+					b.Fatalf("expected 200 images, got %d", len(analysis.Images))
+				}
+
+				k8sClient.recordHeap()
+				if k8sClient.peakHeap > baseline && k8sClient.peakHeap-baseline > peakHeap {
+					peakHeap = k8sClient.peakHeap - baseline
+				}
+			}
+			b.ReportMetric(float64(peakHeap), "peak-heap-bytes")
+		})
+	}
+}
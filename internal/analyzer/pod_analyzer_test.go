@@ -2,11 +2,14 @@ package analyzer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -217,3 +220,489 @@ func TestPodAnalyzer_AnalyzePods_MultipleNamespaces(t *testing.T) {
 	// Assert result has 2 images (all from node)
 	assert.Len(t, result.Images, 2)
 }
+
+func TestPodAnalyzer_AnalyzePods_MatchByDigest(t *testing.T) {
+	ctx := context.Background()
+
+	nginxDigest := "sha256:" + strings.Repeat("a", 64)
+
+	// Pod references a tag that is not listed on the node, but its status imageID
+	// resolves to a digest the node does have
+	pod1 := createTestPod("pod1", "default", "nginx:stable")
+	pod1.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "container-0", ImageID: "docker-pullable://nginx@" + nginxDigest},
+	}
+
+	// Short name reference matches the fully qualified node name
+	pod2 := createTestPod("pod2", "default", "redis")
+
+	node1 := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: corev1.NodeStatus{Images: []corev1.ContainerImage{
+			{
+				Names:     []string{"docker.io/library/nginx@" + nginxDigest, "docker.io/library/nginx:1.21"},
+				SizeBytes: 100000000,
+			},
+			{
+				Names:     []string{"docker.io/library/redis:latest"},
+				SizeBytes: 50000000,
+			},
+		}},
+	}
+
+	fakeK8s := kubernetes.NewFakeClient(pod1, pod2, node1)
+	clusterClient := cluster.NewClient(fakeK8s)
+	podAnalyzer := NewPodAnalyzer(clusterClient, types.DefaultAnalysisConfig())
+
+	result, err := podAnalyzer.AnalyzePods(ctx, "default", "")
+	require.NoError(t, err)
+
+	imageMap := make(map[string]types.Image)
+	for _, img := range result.Images {
+		imageMap[img.Name] = img
+	}
+
+	assert.Len(t, result.Images, 2)
+	nginxImg, exists := imageMap["docker.io/library/nginx:1.21"]
+	assert.True(t, exists, "nginx should resolve by digest")
+	assert.False(t, nginxImg.Inaccessible)
+	redisImg, exists := imageMap["docker.io/library/redis:latest"]
+	assert.True(t, exists, "redis should resolve by normalized name")
+	assert.False(t, redisImg.Inaccessible)
+	assert.Equal(t, int64(150000000), result.TotalSize)
+}
+
+func TestPodAnalyzer_AnalyzePods_TagDrift(t *testing.T) {
+	ctx := context.Background()
+
+	oldDigest := "sha256:" + strings.Repeat("a", 64)
+	newDigest := "sha256:" + strings.Repeat("b", 64)
+
+	pod1 := createTestPod("web-1", "default", "acme/app:stable")
+	pod1.Spec.NodeName = "node1"
+	pod1.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "container-0", ImageID: "docker.io/acme/app@" + oldDigest},
+	}
+	pod2 := createTestPod("web-2", "default", "acme/app:stable")
+	pod2.Spec.NodeName = "node2"
+	pod2.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "container-0", ImageID: "docker.io/acme/app@" + newDigest},
+	}
+	nodeWith := func(name, digest string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Images: []corev1.ContainerImage{
+				{Names: []string{"docker.io/acme/app@" + digest, "docker.io/acme/app:stable"}, SizeBytes: 100000000},
+			}},
+		}
+	}
+
+	fakeK8s := kubernetes.NewFakeClient(pod1, pod2, nodeWith("node1", oldDigest), nodeWith("node2", newDigest))
+	config := types.DefaultAnalysisConfig()
+	config.TagDrift = true
+	podAnalyzer := NewPodAnalyzer(cluster.NewClient(fakeK8s), config)
+
+	result, err := podAnalyzer.AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+
+	require.Len(t, result.TagDrift, 1)
+	assert.Equal(t, "docker.io/acme/app:stable", result.TagDrift[0].Image)
+	assert.Equal(t, []types.DigestUsage{
+		{Digest: oldDigest, Nodes: []string{"node1"}, Pods: []string{"default/web-1"}},
+		{Digest: newDigest, Nodes: []string{"node2"}, Pods: []string{"default/web-2"}},
+	}, result.TagDrift[0].Digests)
+}
+
+func TestPodAnalyzer_AnalyzePods_ContainerTypes(t *testing.T) {
+	ctx := context.Background()
+
+	pod1 := createTestPod("web", "default", "nginx:1.21")
+	pod1.Spec.EphemeralContainers = []corev1.EphemeralContainer{
+		{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox:1.36"}},
+	}
+	node1 := createTestNode("node1", map[string]int64{
+		"nginx:1.21":   100000000,
+		"busybox:1.36": 5000000,
+	})
+	fakeK8s := kubernetes.NewFakeClient(pod1, node1)
+
+	// Grouping reports debug images alongside the regular ones
+	config := types.DefaultAnalysisConfig()
+	config.GroupBy = types.GroupByContainerType
+	result, err := NewPodAnalyzer(cluster.NewClient(fakeK8s), config).AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []types.ContainerTypeUsage{
+		{Type: types.ContainerTypeRegular, ImageCount: 1, TotalBytes: 100000000, ClusterBytes: 100000000},
+		{Type: types.ContainerTypeEphemeral, ImageCount: 1, TotalBytes: 5000000, ClusterBytes: 5000000},
+	}, result.ContainerTypes)
+
+	// Filtering keeps only the images of the selected container types
+	config = types.DefaultAnalysisConfig()
+	config.ContainerTypes = []string{types.ContainerTypeEphemeral}
+	result, err = NewPodAnalyzer(cluster.NewClient(fakeK8s), config).AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+
+	require.Len(t, result.Images, 1)
+	assert.Equal(t, "busybox:1.36", result.Images[0].Name)
+	assert.Equal(t, []string{types.ContainerTypeEphemeral}, result.Images[0].ContainerTypes)
+}
+
+func TestPodAnalyzer_AnalyzePods_ContainerTypeMatchesNothing(t *testing.T) {
+	ctx := context.Background()
+
+	pod1 := createTestPod("web", "default", "nginx:1.21")
+	node1 := createTestNode("node1", map[string]int64{
+		"nginx:1.21":   100000000,
+		"busybox:1.36": 5000000,
+	})
+	fakeK8s := kubernetes.NewFakeClient(pod1, node1)
+
+	config := types.DefaultAnalysisConfig()
+	config.ContainerTypes = []string{types.ContainerTypeEphemeral}
+	config.GroupBy = types.GroupByContainerType
+	result, err := NewPodAnalyzer(cluster.NewClient(fakeK8s), config).AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+
+	// No container is ephemeral, so no image is reported, not every node image
+	assert.Empty(t, result.Images)
+	assert.Empty(t, result.ContainerTypes)
+	assert.Equal(t, int64(0), result.TotalSize)
+}
+
+func TestPodAnalyzer_AnalyzePods_PodFilterMatchesNothing(t *testing.T) {
+	ctx := context.Background()
+
+	pod1 := createTestPod("job", "default", "nginx:1.21")
+	pod1.Status.Phase = corev1.PodSucceeded
+	node1 := createTestNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"redis:6.2":  50000000,
+	})
+	// The snapshot client evaluates field selectors, which the fake clientset ignores
+	snapshot := &kubernetes.Snapshot{Pods: []corev1.Pod{*pod1}, Nodes: []corev1.Node{*node1}}
+
+	tests := []struct {
+		name      string
+		podFilter types.PodFilter
+	}{
+		{name: "no pod in phase", podFilter: types.PodFilter{Phases: []string{"Running"}}},
+		{name: "no pod on node", podFilter: types.PodFilter{FieldSelector: "spec.nodeName=node2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := types.DefaultAnalysisConfig()
+			config.PodFilter = tt.podFilter
+			clusterClient := cluster.NewClient(kubernetes.NewSnapshotClient(snapshot))
+
+			result, err := NewPodAnalyzer(clusterClient, config).AnalyzePods(ctx, "", "")
+			require.NoError(t, err)
+
+			// Pods were listed but none matched, so no node image is reported
+			assert.Empty(t, result.Images)
+			assert.Equal(t, int64(0), result.TotalSize)
+		})
+	}
+}
+
+func TestPodAnalyzer_AnalyzePods_GroupByNamespace(t *testing.T) {
+	ctx := context.Background()
+
+	pod1 := createTestPod("pod1", "team-a", "nginx:1.21", "app-a:v1")
+	pod2 := createTestPod("pod2", "team-b", "nginx:1.21")
+	node1 := createTestNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"app-a:v1":   300000000,
+		"unused:v1":  50000000,
+	})
+
+	fakeK8s := kubernetes.NewFakeClient(pod1, pod2, node1)
+	clusterClient := cluster.NewClient(fakeK8s)
+	config := types.DefaultAnalysisConfig()
+	config.GroupBy = types.GroupByNamespace
+	podAnalyzer := NewPodAnalyzer(clusterClient, config)
+
+	// No filters: pods are still listed so images can be attributed
+	result, err := podAnalyzer.AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+
+	// Only images used by pods are reported
+	assert.Len(t, result.Images, 2)
+	require.Len(t, result.Namespaces, 2)
+
+	assert.Equal(t, "team-a", result.Namespaces[0].Namespace)
+	assert.Equal(t, 2, result.Namespaces[0].ImageCount)
+	assert.Equal(t, int64(300000000), result.Namespaces[0].UniqueBytes)
+	assert.Equal(t, int64(100000000), result.Namespaces[0].SharedBytes)
+
+	assert.Equal(t, "team-b", result.Namespaces[1].Namespace)
+	assert.Equal(t, 1, result.Namespaces[1].ImageCount)
+	assert.Equal(t, int64(0), result.Namespaces[1].UniqueBytes)
+	assert.Equal(t, int64(100000000), result.Namespaces[1].SharedBytes)
+}
+
+func TestPodAnalyzer_AnalyzePods_GroupByRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	pod1 := createTestPod("pod1", "team-a", "nginx:1.21", "ghcr.io/acme/api:v1")
+	pod2 := createTestPod("pod2", "team-b", "nginx:1.22")
+	node1 := createTestNode("node1", map[string]int64{
+		"nginx:1.21":          100000000,
+		"nginx:1.22":          100000000,
+		"ghcr.io/acme/api:v1": 300000000,
+	})
+
+	fakeK8s := kubernetes.NewFakeClient(pod1, pod2, node1)
+	clusterClient := cluster.NewClient(fakeK8s)
+
+	config := types.DefaultAnalysisConfig()
+	config.GroupBy = types.GroupByRegistry
+	result, err := NewPodAnalyzer(clusterClient, config).AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+
+	// Registry grouping does not list pods, so every image on the nodes is reported
+	assert.Len(t, result.Images, 3)
+	assert.Empty(t, result.Namespaces)
+	require.Len(t, result.Registries, 2)
+	assert.Equal(t, "ghcr.io", result.Registries[0].Name)
+	assert.Equal(t, "docker.io", result.Registries[1].Name)
+	assert.Equal(t, 2, result.Registries[1].ImageCount)
+	assert.InDelta(t, 0.4, result.Registries[1].Share, 0.001)
+
+	config.GroupBy = types.GroupByRepository
+	result, err = NewPodAnalyzer(clusterClient, config).AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+
+	require.Len(t, result.Repositories, 2)
+	assert.Equal(t, "ghcr.io/acme/api", result.Repositories[0].Name)
+	assert.Equal(t, "docker.io/library/nginx", result.Repositories[1].Name)
+	assert.Equal(t, int64(200000000), result.Repositories[1].TotalBytes)
+}
+
+func TestPodAnalyzer_AnalyzePods_GroupByWorkload(t *testing.T) {
+	ctx := context.Background()
+	controller := true
+
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-5d4f8",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}},
+		},
+	}
+	pod1 := createTestPod("web-1", "default", "nginx:1.21")
+	pod1.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-5d4f8", Controller: &controller}}
+	pod1.Spec.NodeName = "node1"
+	pod2 := createTestPod("web-2", "default", "nginx:1.21")
+	pod2.OwnerReferences = pod1.OwnerReferences
+	pod2.Spec.NodeName = "node2"
+	pod3 := createTestPod("debug", "default", "busybox:1.36")
+	pod3.Spec.NodeName = "node1"
+
+	node1 := createTestNode("node1", map[string]int64{
+		"nginx:1.21":   100000000,
+		"busybox:1.36": 5000000,
+	})
+
+	fakeK8s := kubernetes.NewFakeClient(rs, pod1, pod2, pod3, node1)
+	clusterClient := cluster.NewClient(fakeK8s)
+	config := types.DefaultAnalysisConfig()
+	config.GroupBy = types.GroupByWorkload
+	podAnalyzer := NewPodAnalyzer(clusterClient, config)
+
+	result, err := podAnalyzer.AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+	require.Len(t, result.Workloads, 2)
+
+	web := result.Workloads[0]
+	assert.Equal(t, "Deployment/web", web.String())
+	assert.Equal(t, 2, web.Replicas)
+	assert.Equal(t, 2, web.NodeCount)
+	assert.Equal(t, int64(100000000), web.TotalBytes)
+
+	debug := result.Workloads[1]
+	assert.Equal(t, "Pod/debug", debug.String())
+	assert.Equal(t, 1, debug.Replicas)
+	assert.Equal(t, int64(5000000), debug.TotalBytes)
+}
+
+func TestPodAnalyzer_AnalyzePods_ClusterSize(t *testing.T) {
+	ctx := context.Background()
+
+	// nginx is cached on three nodes, redis on one
+	node1 := createTestNode("node1", map[string]int64{"nginx:1.21": 100000000, "redis:6.2": 50000000})
+	node2 := createTestNode("node2", map[string]int64{"nginx:1.21": 100000000})
+	node3 := createTestNode("node3", map[string]int64{"nginx:1.21": 100000000})
+
+	fakeK8s := kubernetes.NewFakeClient(node1, node2, node3)
+	clusterClient := cluster.NewClient(fakeK8s)
+	podAnalyzer := NewPodAnalyzer(clusterClient, types.DefaultAnalysisConfig())
+
+	result, err := podAnalyzer.AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+
+	imageMap := make(map[string]types.Image)
+	for _, img := range result.Images {
+		imageMap[img.Name] = img
+	}
+
+	nginxImg := imageMap["nginx:1.21"]
+	assert.Equal(t, []string{"node1", "node2", "node3"}, nginxImg.Nodes)
+	assert.Equal(t, 3, nginxImg.NodeCount)
+	assert.Equal(t, int64(300000000), nginxImg.ClusterSize)
+
+	redisImg := imageMap["redis:6.2"]
+	assert.Equal(t, 1, redisImg.NodeCount)
+	assert.Equal(t, int64(50000000), redisImg.ClusterSize)
+
+	// Total size counts each image once, cluster size counts every copy
+	assert.Equal(t, int64(150000000), result.TotalSize)
+	assert.Equal(t, int64(150000000), result.UniqueSize)
+	assert.Equal(t, int64(350000000), result.ClusterSize)
+}
+
+// failingClient fails pod and node lists with the configured errors
+type failingClient struct {
+	kubernetes.Interface
+	podErr, nodeErr error
+}
+
+func (f *failingClient) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	if f.podErr != nil {
+		return nil, f.podErr
+	}
+	return f.Interface.ListPods(ctx, namespace, opts)
+}
+
+func (f *failingClient) ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	if f.nodeErr != nil {
+		return nil, f.nodeErr
+	}
+	return f.Interface.ListNodes(ctx, opts)
+}
+
+func TestPodAnalyzer_AnalyzePods_ConcurrentQueries(t *testing.T) {
+	ctx := context.Background()
+	errPods := errors.New("pods unavailable")
+	errNodes := errors.New("nodes unavailable")
+
+	tests := []struct {
+		name        string
+		podErr      error
+		nodeErr     error
+		expectError []string
+	}{
+		{name: "both queries succeed"},
+		{name: "pod query fails", podErr: errPods, expectError: []string{"failed to list pods", "pods unavailable"}},
+		{name: "node query fails", nodeErr: errNodes, expectError: []string{"failed to get image sizes from nodes", "nodes unavailable"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeK8s := &failingClient{
+				Interface: kubernetes.NewFakeClient(
+					createTestPod("web", "team-a", "nginx:1.21"),
+					createTestPod("api", "team-b", "api:v1"),
+					createTestNode("node1", map[string]int64{"nginx:1.21": 100000000, "api:v1": 50000000}),
+				),
+				podErr:  tt.podErr,
+				nodeErr: tt.nodeErr,
+			}
+			podAnalyzer := NewPodAnalyzer(cluster.NewClient(fakeK8s), types.DefaultAnalysisConfig())
+
+			analysis, err := podAnalyzer.AnalyzePodsIn(ctx, types.NamespaceFilter{Include: []string{"team-a", "team-b"}}, "")
+			if len(tt.expectError) > 0 {
+				require.Error(t, err)
+				for _, msg := range tt.expectError {
+					assert.Contains(t, err.Error(), msg)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, analysis.Images, 2)
+			perf := analysis.Performance
+			require.NotNil(t, perf)
+			assert.Positive(t, perf.QueryTime)
+			assert.GreaterOrEqual(t, perf.SummedQueryTime, perf.NodeQueryTime)
+		})
+	}
+}
+
+// limitClient records the page size of each pod and node list
+type limitClient struct {
+	kubernetes.Interface
+	podLimits, nodeLimits []int64
+}
+
+func (c *limitClient) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	c.podLimits = append(c.podLimits, opts.Limit)
+	return c.Interface.ListPods(ctx, namespace, opts)
+}
+
+func (c *limitClient) ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	c.nodeLimits = append(c.nodeLimits, opts.Limit)
+	return c.Interface.ListNodes(ctx, opts)
+}
+
+func TestPodAnalyzer_AnalyzePods_PageSizes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		podPageSize  int64
+		nodePageSize int64
+	}{
+		{name: "configured page sizes", podPageSize: 7, nodePageSize: 3},
+		{name: "unpaginated", podPageSize: 0, nodePageSize: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := &limitClient{Interface: kubernetes.NewFakeClient(
+				createTestPod("web", "default", "nginx:1.21"),
+				createTestNode("node1", map[string]int64{"nginx:1.21": 100000000}),
+			)}
+			config := types.DefaultAnalysisConfig()
+			config.ListPods = true
+			config.PodPageSize = tt.podPageSize
+			config.NodePageSize = tt.nodePageSize
+
+			_, err := NewPodAnalyzer(cluster.NewClient(k8sClient), config).AnalyzePods(ctx, "", "")
+			require.NoError(t, err)
+
+			// The analyzer applies the configured page sizes to its client
+			require.NotEmpty(t, k8sClient.podLimits)
+			require.NotEmpty(t, k8sClient.nodeLimits)
+			assert.Equal(t, tt.podPageSize, k8sClient.podLimits[0])
+			assert.Equal(t, tt.nodePageSize, k8sClient.nodeLimits[0])
+		})
+	}
+}
+
+func TestNewPodAnalyzer_LeavesClientUnchanged(t *testing.T) {
+	ctx := context.Background()
+
+	k8sClient := &limitClient{Interface: kubernetes.NewFakeClient(
+		createTestNode("node1", map[string]int64{"nginx:1.21": 100000000}),
+		createTestNode("node2", map[string]int64{"redis:6.2": 50000000}),
+	)}
+	clusterClient := cluster.NewClient(k8sClient)
+
+	config := types.DefaultAnalysisConfig()
+	config.NodePageSize = 3
+	config.NodeFilter = types.NodeFilter{Names: []string{"node1"}}
+	result, err := NewPodAnalyzer(clusterClient, config).AnalyzePods(ctx, "", "")
+	require.NoError(t, err)
+	require.Len(t, result.Images, 1)
+	assert.Equal(t, "nginx:1.21", result.Images[0].Name)
+
+	// The caller's client, e.g. shared with a node analyzer, keeps its page sizes and lists every node
+	k8sClient.nodeLimits = nil
+	sizes, _, err := clusterClient.GetImageSizesFromNodes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sizes, 2)
+	require.NotEmpty(t, k8sClient.nodeLimits)
+	assert.Equal(t, int64(types.DefaultNodePageSize), k8sClient.nodeLimits[0])
+}
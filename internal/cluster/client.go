@@ -3,170 +3,414 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/briandowns/spinner"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/tools/pager"
 
 	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
 	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
 )
 
-// Client represents a Kubernetes cluster client
+// Client represents a Kubernetes cluster client. Its methods are safe to call
+// concurrently.
 type Client struct {
-	k8sClient kubernetes.Interface
+	k8sClient    kubernetes.Interface
+	podPageSize  int64
+	nodePageSize int64
+	podFilter    types.PodFilter
+	nodeFilter   types.NodeFilter
+	progressOut  io.Writer
+	progress     *progress
 }
 
 // NewClient creates a new Kubernetes client
 func NewClient(k8sClient kubernetes.Interface) *Client {
 	return &Client{
-		k8sClient: k8sClient,
+		k8sClient:    k8sClient,
+		podPageSize:  types.DefaultPodPageSize,
+		nodePageSize: types.DefaultNodePageSize,
+		progressOut:  os.Stderr,
+		progress:     newProgress(os.Stderr),
 	}
 }
 
+// SetProgressOutput sets where the query spinner and results are written.
+// io.Discard silences them, e.g. when analyzing on an interval.
+func (c *Client) SetProgressOutput(w io.Writer) {
+	c.progressOut = w
+	c.progress = newProgress(w)
+}
+
+// ProgressOutput returns where the query spinner and results are written
+func (c *Client) ProgressOutput() io.Writer {
+	return c.progressOut
+}
+
+// Copy returns a copy of the client that shares its Kubernetes client and progress
+// output, so page sizes and filters can be set without affecting other users
+func (c *Client) Copy() *Client {
+	cp := *c
+	return &cp
+}
+
+// SetPageSizes sets the number of pods and nodes to fetch per list page. A page
+// size of 0 lists every object in a single response from the API server's watch cache.
+func (c *Client) SetPageSizes(podPageSize, nodePageSize int64) {
+	c.podPageSize = podPageSize
+	c.nodePageSize = nodePageSize
+}
+
+// SetPodFilter sets the field selector and phases that listed pods must match
+func (c *Client) SetPodFilter(filter types.PodFilter) {
+	c.podFilter = filter
+}
+
+// SetNodeFilter restricts listed nodes, and pods to those scheduled on the
+// selected nodes
+func (c *Client) SetNodeFilter(filter types.NodeFilter) {
+	c.nodeFilter = filter
+}
+
 // ListPods lists pods with optional filters and performance metrics using pager
 func (c *Client) ListPods(ctx context.Context, namespace, labelSelector string) ([]types.Pod, *types.PerformanceMetrics, error) {
-	// Create and start spinner
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
-	if namespace == "" {
-		s.Suffix = " Querying pods from cluster (namespace: All)..."
-	} else {
-		s.Suffix = fmt.Sprintf(" Querying pods from cluster (namespace: %s)...", namespace)
+	var pods []types.Pod
+	metrics, err := c.EachPod(ctx, []string{namespace}, labelSelector, func(pod types.Pod) {
+		pods = append(pods, pod)
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-	_ = s.Color("cyan")
-	s.Start()
-	defer s.Stop()
+	return pods, metrics, nil
+}
 
+// EachPod lists pods with optional filters a page at a time and calls fn for each
+// pod, so callers can fold pods into what they need without holding every pod in
+// memory. An empty namespace list, or an empty namespace, lists pods in all
+// namespaces. Several namespaces are listed concurrently; fn is never called
+// concurrently. Pods not matching the pod filter phases, or not scheduled on a node
+// selected by the node filter, are skipped.
+func (c *Client) EachPod(ctx context.Context, namespaces []string, labelSelector string, fn func(types.Pod)) (*types.PerformanceMetrics, error) {
 	startTime := time.Now()
 
-	var allPods []types.Pod
-	var totalPods int
-
-	// List options with ResourceVersion=0 for watch cache optimization
-	listOptions := metav1.ListOptions{
-		ResourceVersion: "0", // Use watch cache for better performance
+	// Node selectors are resolved to node names, since pods can only be selected by node name
+	nodes := c.nodeFilter
+	if nodes.Selector != "" {
+		var err error
+		nodes, err = c.ResolveNodes(ctx, nodes)
+		if err != nil {
+			return nil, err
+		}
 	}
-	if labelSelector != "" {
-		listOptions.LabelSelector = labelSelector
+	fieldSelector := c.podFilter.FieldSelector
+	if len(nodes.Names) == 1 {
+		fieldSelector = joinFieldSelectors(fieldSelector, "spec.nodeName="+nodes.Names[0])
 	}
 
-	// Use pager to efficiently list all pods
-	pager := pager.New(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
-		return c.k8sClient.ListPods(ctx, namespace, opts)
-	})
-
-	// Set page size for efficient pagination
-	pager.PageSize = 1000
-
-	// List all pods using pager
-	err := pager.EachListItem(ctx, listOptions, func(obj runtime.Object) error {
-		pod := obj.(*corev1.Pod)
-		allPods = append(allPods, types.FromK8sPod(pod))
-		totalPods = len(allPods)
+	display := namespacesDisplay(namespaces)
+	if display == "All" {
+		namespaces = []string{""}
+	}
+	task := c.progress.start(fmt.Sprintf("Querying pods from cluster (namespace: %s)...", display))
 
-		// Update spinner with progress every 100 pods
-		if totalPods%100 == 0 {
-			s.Suffix = fmt.Sprintf(" Querying pods from cluster (namespace: %s)... %d pods found",
-				namespaceDisplay(namespace), totalPods)
+	var mu sync.Mutex
+	var totalPods int
+	var summedQueryTime time.Duration
+
+	queries := make([]func(ctx context.Context) error, len(namespaces))
+	for i, namespace := range namespaces {
+		namespace := namespace
+		queries[i] = func(ctx context.Context) error {
+			queryStart := time.Now()
+			defer func() {
+				mu.Lock()
+				summedQueryTime += time.Since(queryStart)
+				mu.Unlock()
+			}()
+
+			options := kubernetes.ListOptions(c.podPageSize)
+			if labelSelector != "" {
+				options.LabelSelector = labelSelector
+			}
+			options.FieldSelector = fieldSelector
+
+			// Use pager to list pods a page at a time
+			pager := kubernetes.NewPager(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				return c.k8sClient.ListPods(ctx, namespace, opts)
+			}, c.podPageSize)
+
+			err := pager.EachListItem(ctx, options, func(obj runtime.Object) error {
+				pod := types.FromK8sPod(obj.(*corev1.Pod))
+				if !c.podFilter.MatchesPhase(pod.Phase) || (!nodes.IsAll() && !nodes.MatchesName(pod.NodeName)) {
+					return nil
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				fn(pod)
+				totalPods++
+
+				// Update spinner with progress every 100 pods
+				if totalPods%100 == 0 {
+					task.update(fmt.Sprintf("Querying pods from cluster (namespace: %s)... %d pods found", display, totalPods))
+				}
+
+				return nil
+			})
+			if err != nil && namespace != "" {
+				return fmt.Errorf("namespace %s: %w", namespace, err)
+			}
+			return err
 		}
+	}
 
-		return nil
-	})
-
-	if err != nil {
-		s.Stop() // Stop spinner before returning error
-		return nil, nil, fmt.Errorf("failed to list pods: %w", err)
+	if err := runQueries(ctx, maxNamespaceQueries, queries); err != nil {
+		task.done("")
+		return nil, fmt.Errorf("failed to list pods: %w", err)
 	}
 
 	podQueryTime := time.Since(startTime)
-	s.Stop() // Stop spinner before success message
 
 	// Show success message with pod count
-	if namespace == "" {
-		fmt.Fprintf(os.Stderr, "✓ Found %d pods across all namespaces (query time: %v)\n", totalPods, podQueryTime)
+	if display == "All" {
+		task.done("✓ Found %d pods across all namespaces (query time: %v)\n", totalPods, podQueryTime)
+	} else if len(namespaces) == 1 {
+		task.done("✓ Found %d pods in namespace %s (query time: %v)\n", totalPods, display, podQueryTime)
 	} else {
-		fmt.Fprintf(os.Stderr, "✓ Found %d pods in namespace %s (query time: %v)\n", totalPods, namespace, podQueryTime)
+		task.done("✓ Found %d pods in namespaces %s (query time: %v)\n", totalPods, display, podQueryTime)
 	}
 
 	metrics := &types.PerformanceMetrics{
-		PodQueryTime: podQueryTime,
+		PodQueryTime:    podQueryTime,
+		SummedQueryTime: summedQueryTime,
 	}
 
-	return allPods, metrics, nil
+	return metrics, nil
 }
 
-// GetImageSizesFromNodes gets image sizes from node status
+// GetImageSizesFromNodes gets image sizes from node status keyed by canonical image name
 func (c *Client) GetImageSizesFromNodes(ctx context.Context) (map[string]int64, *types.PerformanceMetrics, error) {
-	// Create and start spinner
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
-	s.Suffix = " Querying image sizes from nodes..."
-	_ = s.Color("cyan")
-	s.Start()
-	defer s.Stop()
+	index, metrics, err := c.GetImageIndexFromNodes(ctx, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return index.Sizes, metrics, nil
+}
 
-	startTime := time.Now()
+// GetImageIndexFromNodes builds an index of every image name and digest reported in
+// node status. With tagDigests, the digest each node holds for each tag is indexed
+// as well, for tag drift detection.
+func (c *Client) GetImageIndexFromNodes(ctx context.Context, tagDigests bool) (*types.NodeImageIndex, *types.PerformanceMetrics, error) {
+	task := c.progress.start("Querying image sizes from nodes...")
 
-	// List options with ResourceVersion=0 for watch cache optimization
-	listOptions := metav1.ListOptions{
-		ResourceVersion: "0", // Use watch cache for better performance
-	}
+	startTime := time.Now()
 
-	// Use pager to efficiently list all nodes
-	pager := pager.New(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+	// Use pager to list nodes a page at a time; each page is dropped once its
+	// images are indexed
+	pager := kubernetes.NewPager(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
 		return c.k8sClient.ListNodes(ctx, opts)
-	})
-
-	// Set page size for efficient pagination
-	pager.PageSize = 1000
+	}, c.nodePageSize)
 
-	// Extract image sizes from node status
-	imageSizes := make(map[string]int64)
+	// Index image names, digests and sizes from node status
+	index := types.NewNodeImageIndex()
 	var totalImages int
 	var totalNodes int
 
 	// List all nodes using pager
-	err := pager.EachListItem(ctx, listOptions, func(obj runtime.Object) error {
+	options := kubernetes.ListOptions(c.nodePageSize)
+	options.LabelSelector = c.nodeFilter.Selector
+	err := pager.EachListItem(ctx, options, func(obj runtime.Object) error {
 		node := obj.(*corev1.Node)
+		if !c.nodeFilter.MatchesName(node.Name) {
+			return nil
+		}
 		totalNodes++
 
 		for _, image := range node.Status.Images {
 			if len(image.Names) > 0 {
 				// Select the best canonical name
 				imageName := selectBestImageName(image.Names)
-				imageSizes[imageName] = image.SizeBytes
+				index.Add(imageName, image.Names, image.SizeBytes)
+				index.AddNode(imageName, node.Name)
+				if tagDigests {
+					index.AddTagDigests(image.Names, node.Name)
+				}
 				totalImages++
 			}
 		}
 
 		// Update spinner with progress every 10 nodes
 		if totalNodes%10 == 0 {
-			s.Suffix = fmt.Sprintf(" Querying image sizes from nodes... %d nodes processed", totalNodes)
+			task.update(fmt.Sprintf("Querying image sizes from nodes... %d nodes processed", totalNodes))
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		s.Stop()
+		task.done("")
 		return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
 	}
 
 	nodeQueryTime := time.Since(startTime)
-	s.Stop()
+	task.done("✓ Found %d unique images from %d nodes (query time: %v)\n",
+		len(index.Sizes), totalNodes, nodeQueryTime)
+
+	metrics := &types.PerformanceMetrics{
+		NodeQueryTime: nodeQueryTime,
+	}
 
-	fmt.Fprintf(os.Stderr, "✓ Found %d unique images from %d nodes (query time: %v)\n",
-		len(imageSizes), totalNodes, nodeQueryTime)
+	return index, metrics, nil
+}
+
+// ListNodes lists all nodes with their cached images and ephemeral storage
+func (c *Client) ListNodes(ctx context.Context) ([]types.Node, *types.PerformanceMetrics, error) {
+	task := c.progress.start("Querying nodes from cluster...")
+
+	startTime := time.Now()
+
+	// Use pager to list nodes a page at a time
+	pager := kubernetes.NewPager(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.k8sClient.ListNodes(ctx, opts)
+	}, c.nodePageSize)
+
+	var nodes []types.Node
+	options := kubernetes.ListOptions(c.nodePageSize)
+	options.LabelSelector = c.nodeFilter.Selector
+	err := pager.EachListItem(ctx, options, func(obj runtime.Object) error {
+		if !c.nodeFilter.MatchesName(obj.(*corev1.Node).Name) {
+			return nil
+		}
+		node := types.FromK8sNode(obj.(*corev1.Node))
+		for i := range node.Images {
+			node.Images[i].Name = selectBestImageName(node.Images[i].Names)
+		}
+		nodes = append(nodes, node)
+
+		// Update spinner with progress every 10 nodes
+		if len(nodes)%10 == 0 {
+			task.update(fmt.Sprintf("Querying nodes from cluster... %d nodes processed", len(nodes)))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		task.done("")
+		return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	nodeQueryTime := time.Since(startTime)
+	task.done("✓ Found %d nodes (query time: %v)\n", len(nodes), nodeQueryTime)
 
 	metrics := &types.PerformanceMetrics{
 		NodeQueryTime: nodeQueryTime,
 	}
 
-	return imageSizes, metrics, nil
+	return nodes, metrics, nil
+}
+
+// GetWorkloadOwners maps ReplicaSets and Jobs to their controlling owners so that
+// pods can be resolved to Deployments and CronJobs rather than intermediate controllers
+func (c *Client) GetWorkloadOwners(ctx context.Context, namespace string) (map[types.Workload]types.Workload, *types.PerformanceMetrics, error) {
+	task := c.progress.start(fmt.Sprintf("Querying workload owners (namespace: %s)...", namespaceDisplay(namespace)))
+
+	startTime := time.Now()
+
+	owners := make(map[types.Workload]types.Workload)
+	addOwner := func(kind string, obj metav1.Object) {
+		if owner := types.ControllerOf(obj); owner != nil {
+			owners[types.Workload{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName()}] = *owner
+		}
+	}
+
+	// Resolve ReplicaSet -> Deployment
+	rsPager := kubernetes.NewPager(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.k8sClient.ListReplicaSets(ctx, namespace, opts)
+	}, types.DefaultOwnerPageSize)
+	err := rsPager.EachListItem(ctx, kubernetes.ListOptions(types.DefaultOwnerPageSize), func(obj runtime.Object) error {
+		addOwner("ReplicaSet", obj.(*appsv1.ReplicaSet))
+		return nil
+	})
+	if err != nil {
+		task.done("")
+		return nil, nil, fmt.Errorf("failed to list replica sets: %w", err)
+	}
+
+	// Resolve Job -> CronJob
+	jobPager := kubernetes.NewPager(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.k8sClient.ListJobs(ctx, namespace, opts)
+	}, types.DefaultOwnerPageSize)
+	err = jobPager.EachListItem(ctx, kubernetes.ListOptions(types.DefaultOwnerPageSize), func(obj runtime.Object) error {
+		addOwner("Job", obj.(*batchv1.Job))
+		return nil
+	})
+	if err != nil {
+		task.done("")
+		return nil, nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	ownerQueryTime := time.Since(startTime)
+	task.done("✓ Resolved %d workload owners (query time: %v)\n", len(owners), ownerQueryTime)
+
+	metrics := &types.PerformanceMetrics{
+		OwnerQueryTime: ownerQueryTime,
+	}
+
+	return owners, metrics, nil
+}
+
+// ResolveNamespaces resolves the namespace selector of the filter into the list
+// of matching namespaces, and drops excluded namespaces from the included ones.
+// The result has no selector; with no included namespaces it selects all
+// namespaces but the excluded ones.
+func (c *Client) ResolveNamespaces(ctx context.Context, filter types.NamespaceFilter) (types.NamespaceFilter, error) {
+	resolved := types.NamespaceFilter{Exclude: filter.Exclude}
+	notExcluded := types.NamespaceFilter{Exclude: filter.Exclude}
+
+	include := filter.Include
+	if filter.Selector != "" {
+		options := kubernetes.ListOptions(types.DefaultNamespacePageSize)
+		options.LabelSelector = filter.Selector
+
+		pager := kubernetes.NewPager(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return c.k8sClient.ListNamespaces(ctx, opts)
+		}, types.DefaultNamespacePageSize)
+
+		selected := types.NamespaceFilter{Include: filter.Include}
+		include = nil
+		err := pager.EachListItem(ctx, options, func(obj runtime.Object) error {
+			name := obj.(*corev1.Namespace).Name
+			if selected.Matches(name) {
+				include = append(include, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return types.NamespaceFilter{}, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		if len(include) == 0 {
+			return types.NamespaceFilter{}, fmt.Errorf("no namespaces match the namespace selector %q", filter.Selector)
+		}
+	}
+
+	for _, namespace := range include {
+		if notExcluded.Matches(namespace) {
+			resolved.Include = append(resolved.Include, namespace)
+		}
+	}
+	if len(include) > 0 && len(resolved.Include) == 0 {
+		return types.NamespaceFilter{}, fmt.Errorf("all selected namespaces are excluded")
+	}
+
+	return resolved, nil
 }
 
 // namespaceDisplay returns a display name for the namespace
@@ -177,6 +421,63 @@ func namespaceDisplay(namespace string) string {
 	return namespace
 }
 
+// namespacesDisplay returns a display name for a list of namespaces, where an
+// empty list or an empty namespace means all namespaces
+func namespacesDisplay(namespaces []string) string {
+	if len(namespaces) == 0 {
+		return "All"
+	}
+	for _, namespace := range namespaces {
+		if namespace == "" {
+			return "All"
+		}
+	}
+	return strings.Join(namespaces, ", ")
+}
+
+// ResolveNodes resolves the node selector of a filter into the names of the
+// matching nodes, keeping only those among the filter's names when both are set
+func (c *Client) ResolveNodes(ctx context.Context, filter types.NodeFilter) (types.NodeFilter, error) {
+	if filter.Selector == "" {
+		return filter, nil
+	}
+
+	options := kubernetes.ListOptions(c.nodePageSize)
+	options.LabelSelector = filter.Selector
+
+	pager := kubernetes.NewPager(func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.k8sClient.ListNodes(ctx, opts)
+	}, c.nodePageSize)
+
+	var resolved types.NodeFilter
+	err := pager.EachListItem(ctx, options, func(obj runtime.Object) error {
+		name := obj.(*corev1.Node).Name
+		if filter.MatchesName(name) {
+			resolved.Names = append(resolved.Names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return types.NodeFilter{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	if len(resolved.Names) == 0 {
+		return types.NodeFilter{}, fmt.Errorf("no nodes match the node selector %q", filter.Selector)
+	}
+
+	return resolved, nil
+}
+
+// joinFieldSelectors combines field selectors so that objects must match all of them
+func joinFieldSelectors(selectors ...string) string {
+	var nonEmpty []string
+	for _, s := range selectors {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return strings.Join(nonEmpty, ",")
+}
+
 // GetUniqueImages extracts unique images from pods
 func (c *Client) GetUniqueImages(pods []types.Pod) map[string]bool {
 	uniqueImages := make(map[string]bool)
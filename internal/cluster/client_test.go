@@ -3,10 +3,14 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -310,3 +314,446 @@ func TestSelectBestImageName(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetWorkloadOwners(t *testing.T) {
+	ctx := context.Background()
+	controller := true
+
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-5d4f8",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}},
+		},
+	}
+	orphanRS := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "default"},
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "backup-28000",
+			Namespace:       "ops",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "backup", Controller: &controller}},
+		},
+	}
+
+	fakeK8s := kubernetes.NewFakeClient(rs, orphanRS, job)
+	clusterClient := NewClient(fakeK8s)
+
+	owners, metrics, err := clusterClient.GetWorkloadOwners(ctx, "")
+	require.NoError(t, err)
+	assert.NotNil(t, metrics)
+
+	assert.Equal(t, map[types.Workload]types.Workload{
+		{Kind: "ReplicaSet", Namespace: "default", Name: "web-5d4f8"}: {Kind: "Deployment", Namespace: "default", Name: "web"},
+		{Kind: "Job", Namespace: "ops", Name: "backup-28000"}:         {Kind: "CronJob", Namespace: "ops", Name: "backup"},
+	}, owners)
+
+	// Namespace filter only resolves owners in that namespace
+	owners, _, err = clusterClient.GetWorkloadOwners(ctx, "ops")
+	require.NoError(t, err)
+	assert.Len(t, owners, 1)
+}
+
+func TestClient_ListNodes(t *testing.T) {
+	ctx := context.Background()
+
+	digest := "sha256:" + strings.Repeat("a", 64)
+	node1 := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: corev1.NodeStatus{Images: []corev1.ContainerImage{
+			{Names: []string{"docker.io/library/nginx@" + digest, "docker.io/library/nginx:1.21"}, SizeBytes: 100000000},
+		}},
+	}
+	node2 := createTestNode("node2", map[string]int64{"nginx:1.21": 110000000})
+
+	fakeK8s := kubernetes.NewFakeClient(node1, node2)
+	clusterClient := NewClient(fakeK8s)
+
+	nodes, metrics, err := clusterClient.ListNodes(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, metrics)
+	require.Len(t, nodes, 2)
+
+	// Each node keeps its own images rather than a flattened cluster-wide map
+	nodesByName := make(map[string]types.Node)
+	for _, node := range nodes {
+		nodesByName[node.Name] = node
+	}
+	require.Len(t, nodesByName["node1"].Images, 1)
+	assert.Equal(t, "docker.io/library/nginx:1.21", nodesByName["node1"].Images[0].Name)
+	assert.Equal(t, int64(100000000), nodesByName["node1"].Images[0].Size)
+	require.Len(t, nodesByName["node2"].Images, 1)
+	assert.Equal(t, int64(110000000), nodesByName["node2"].Images[0].Size)
+}
+
+// pagingClient serves pods and nodes from a FakeClient in pages of the requested
+// limit and records the options of every list call
+type pagingClient struct {
+	kubernetes.Interface
+	podOpts   []metav1.ListOptions
+	nodeOpts  []metav1.ListOptions
+	otherOpts []metav1.ListOptions // ReplicaSet, Job and Namespace lists
+}
+
+func (p *pagingClient) ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error) {
+	p.otherOpts = append(p.otherOpts, opts)
+	return p.Interface.ListReplicaSets(ctx, namespace, opts)
+}
+
+func (p *pagingClient) ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (*batchv1.JobList, error) {
+	p.otherOpts = append(p.otherOpts, opts)
+	return p.Interface.ListJobs(ctx, namespace, opts)
+}
+
+func (p *pagingClient) ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	p.otherOpts = append(p.otherOpts, opts)
+	return p.Interface.ListNamespaces(ctx, opts)
+}
+
+func (p *pagingClient) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	p.podOpts = append(p.podOpts, opts)
+	list, err := p.Interface.ListPods(ctx, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	list.Items, list.Continue = page(list.Items, opts)
+	return list, nil
+}
+
+func (p *pagingClient) ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	p.nodeOpts = append(p.nodeOpts, opts)
+	list, err := p.Interface.ListNodes(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	list.Items, list.Continue = page(list.Items, opts)
+	return list, nil
+}
+
+// page returns the page of items selected by the limit and continue token of
+// the options, and the continue token of the next page
+func page[T any](items []T, opts metav1.ListOptions) ([]T, string) {
+	start, _ := strconv.Atoi(opts.Continue)
+	if opts.Limit == 0 || start+int(opts.Limit) >= len(items) {
+		return items[start:], ""
+	}
+	end := start + int(opts.Limit)
+	return items[start:end], strconv.Itoa(end)
+}
+
+func TestClient_PageSizes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		podPageSize     int64
+		nodePageSize    int64
+		expectPodCalls  int
+		expectNodeCalls int
+		expectRV        string
+	}{
+		{
+			name:            "paginated lists read from etcd",
+			podPageSize:     2,
+			nodePageSize:    1,
+			expectPodCalls:  3,
+			expectNodeCalls: 2,
+			expectRV:        "",
+		},
+		{
+			name:            "page size 0 lists everything from the watch cache",
+			podPageSize:     0,
+			nodePageSize:    0,
+			expectPodCalls:  1,
+			expectNodeCalls: 1,
+			expectRV:        "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paging := &pagingClient{Interface: kubernetes.NewFakeClient(
+				createTestPod("pod1", "default", "nginx:1.21"),
+				createTestPod("pod2", "default", "nginx:1.21"),
+				createTestPod("pod3", "default", "redis:7.0"),
+				createTestPod("pod4", "default", "redis:7.0"),
+				createTestPod("pod5", "default", "busybox:1.36"),
+				createTestNode("node1", map[string]int64{"nginx:1.21": 100000000}),
+				createTestNode("node2", map[string]int64{"redis:7.0": 50000000}),
+			)}
+			clusterClient := NewClient(paging)
+			clusterClient.SetPageSizes(tt.podPageSize, tt.nodePageSize)
+
+			var names []string
+			_, err := clusterClient.EachPod(ctx, nil, "", func(pod types.Pod) {
+				names = append(names, pod.Name)
+			})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"pod1", "pod2", "pod3", "pod4", "pod5"}, names)
+
+			index, _, err := clusterClient.GetImageIndexFromNodes(ctx, false)
+			require.NoError(t, err)
+			assert.Len(t, index.Sizes, 2)
+
+			require.Len(t, paging.podOpts, tt.expectPodCalls)
+			require.Len(t, paging.nodeOpts, tt.expectNodeCalls)
+			for _, opts := range append(paging.podOpts, paging.nodeOpts...) {
+				assert.Equal(t, tt.expectRV, opts.ResourceVersion)
+			}
+			assert.Equal(t, tt.podPageSize, paging.podOpts[0].Limit)
+			assert.Equal(t, tt.nodePageSize, paging.nodeOpts[0].Limit)
+		})
+	}
+}
+
+func TestClient_OwnerAndNamespacePageSizes(t *testing.T) {
+	ctx := context.Background()
+
+	paging := &pagingClient{Interface: kubernetes.NewFakeClient(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "web"}}},
+	)}
+	clusterClient := NewClient(paging)
+	clusterClient.SetPageSizes(0, 0)
+
+	_, _, err := clusterClient.GetWorkloadOwners(ctx, "")
+	require.NoError(t, err)
+	_, err = clusterClient.ResolveNamespaces(ctx, types.NamespaceFilter{Selector: "team=web"})
+	require.NoError(t, err)
+
+	// ReplicaSets, Jobs and Namespaces keep their own page sizes whatever the pod page size
+	require.Len(t, paging.otherOpts, 3)
+	assert.Equal(t, int64(types.DefaultOwnerPageSize), paging.otherOpts[0].Limit)
+	assert.Equal(t, int64(types.DefaultOwnerPageSize), paging.otherOpts[1].Limit)
+	assert.Equal(t, int64(types.DefaultNamespacePageSize), paging.otherOpts[2].Limit)
+	for _, opts := range paging.otherOpts {
+		assert.Empty(t, opts.ResourceVersion)
+	}
+}
+
+func TestClient_EachPod_Namespaces(t *testing.T) {
+	ctx := context.Background()
+
+	fakeK8s := kubernetes.NewFakeClient(
+		createTestPod("web", "team-a", "nginx:1.21"),
+		createTestPod("api", "team-b", "api:v1"),
+		createTestPod("dns", "kube-system", "coredns:1.9"),
+	)
+	clusterClient := NewClient(fakeK8s)
+
+	tests := []struct {
+		name       string
+		namespaces []string
+		expected   []string
+	}{
+		{name: "all namespaces", namespaces: nil, expected: []string{"web", "api", "dns"}},
+		{name: "single namespace", namespaces: []string{"team-a"}, expected: []string{"web"}},
+		{name: "several namespaces listed concurrently", namespaces: []string{"team-a", "team-b"}, expected: []string{"web", "api"}},
+		{name: "empty namespace means all", namespaces: []string{"team-a", ""}, expected: []string{"web", "api", "dns"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			metrics, err := clusterClient.EachPod(ctx, tt.namespaces, "", func(pod types.Pod) {
+				names = append(names, pod.Name)
+			})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, names)
+			assert.NotNil(t, metrics)
+		})
+	}
+}
+
+func TestClient_NodeAndPodFilters(t *testing.T) {
+	ctx := context.Background()
+
+	gpuNode := createTestNode("gpu-1", map[string]int64{"cuda:12": 900000000})
+	gpuNode.Labels = map[string]string{"pool": "gpu"}
+	cpuNode := createTestNode("cpu-1", map[string]int64{"nginx:1.21": 100000000})
+
+	onNode := func(pod *corev1.Pod, node string, phase corev1.PodPhase) *corev1.Pod {
+		pod.Spec.NodeName = node
+		pod.Status.Phase = phase
+		return pod
+	}
+	fakeK8s := kubernetes.NewFakeClient(
+		onNode(createTestPod("train", "ml", "cuda:12"), "gpu-1", corev1.PodRunning),
+		onNode(createTestPod("train-done", "ml", "cuda:12"), "gpu-1", corev1.PodSucceeded),
+		onNode(createTestPod("web", "default", "nginx:1.21"), "cpu-1", corev1.PodRunning),
+		gpuNode, cpuNode,
+	)
+
+	tests := []struct {
+		name          string
+		podFilter     types.PodFilter
+		nodeFilter    types.NodeFilter
+		expectedPods  []string
+		expectedNodes []string
+	}{
+		{
+			name:          "no filters",
+			expectedPods:  []string{"train", "train-done", "web"},
+			expectedNodes: []string{"gpu-1", "cpu-1"},
+		},
+		{
+			name:          "node selector",
+			nodeFilter:    types.NodeFilter{Selector: "pool=gpu"},
+			expectedPods:  []string{"train", "train-done"},
+			expectedNodes: []string{"gpu-1"},
+		},
+		{
+			name:          "node names",
+			nodeFilter:    types.NodeFilter{Names: []string{"cpu-1"}},
+			expectedPods:  []string{"web"},
+			expectedNodes: []string{"cpu-1"},
+		},
+		{
+			name:          "pod phases",
+			podFilter:     types.PodFilter{Phases: []string{"Running"}},
+			expectedPods:  []string{"train", "web"},
+			expectedNodes: []string{"gpu-1", "cpu-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusterClient := NewClient(fakeK8s)
+			clusterClient.SetPodFilter(tt.podFilter)
+			clusterClient.SetNodeFilter(tt.nodeFilter)
+
+			var pods []string
+			_, err := clusterClient.EachPod(ctx, nil, "", func(pod types.Pod) {
+				pods = append(pods, pod.Name)
+			})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expectedPods, pods)
+
+			index, _, err := clusterClient.GetImageIndexFromNodes(ctx, false)
+			require.NoError(t, err)
+			var nodes []string
+			for _, holders := range index.Nodes {
+				nodes = append(nodes, holders...)
+			}
+			assert.ElementsMatch(t, tt.expectedNodes, nodes)
+		})
+	}
+
+	// A node selector matching no nodes is an error rather than an empty report
+	clusterClient := NewClient(fakeK8s)
+	clusterClient.SetNodeFilter(types.NodeFilter{Selector: "pool=tpu"})
+	_, err := clusterClient.EachPod(ctx, nil, "", func(types.Pod) {})
+	assert.ErrorContains(t, err, `no nodes match the node selector "pool=tpu"`)
+}
+
+func TestClient_ResolveNamespaces(t *testing.T) {
+	ctx := context.Background()
+
+	namespace := func(name, team string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"team": team}}}
+	}
+	clusterClient := NewClient(kubernetes.NewFakeClient(
+		namespace("payments-api", "payments"),
+		namespace("payments-jobs", "payments"),
+		namespace("search", "search"),
+	))
+
+	tests := []struct {
+		name          string
+		filter        types.NamespaceFilter
+		expectInclude []string
+		expectError   string
+	}{
+		{
+			name:          "selector resolves to matching namespaces",
+			filter:        types.NamespaceFilter{Selector: "team=payments"},
+			expectInclude: []string{"payments-api", "payments-jobs"},
+		},
+		{
+			name:          "selector is intersected with included namespaces",
+			filter:        types.NamespaceFilter{Include: []string{"payments-api", "search"}, Selector: "team=payments"},
+			expectInclude: []string{"payments-api"},
+		},
+		{
+			name:          "excluded namespaces are dropped",
+			filter:        types.NamespaceFilter{Selector: "team=payments", Exclude: []string{"payments-jobs"}},
+			expectInclude: []string{"payments-api"},
+		},
+		{
+			name:          "exclusion without selector needs no listing",
+			filter:        types.NamespaceFilter{Include: []string{"search", "unknown"}, Exclude: []string{"unknown"}},
+			expectInclude: []string{"search"},
+		},
+		{
+			name:        "selector matching nothing",
+			filter:      types.NamespaceFilter{Selector: "team=billing"},
+			expectError: "no namespaces match the namespace selector",
+		},
+		{
+			name:        "every included namespace excluded",
+			filter:      types.NamespaceFilter{Include: []string{"search"}, Exclude: []string{"search"}},
+			expectError: "all selected namespaces are excluded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := clusterClient.ResolveNamespaces(ctx, tt.filter)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expectInclude, resolved.Include)
+			assert.Empty(t, resolved.Selector)
+			assert.Equal(t, tt.filter.Exclude, resolved.Exclude)
+		})
+	}
+}
+
+func TestClient_SetProgressOutput(t *testing.T) {
+	clusterClient := NewClient(kubernetes.NewFakeClient(createTestNode("node1", map[string]int64{"nginx:1.21": 100000000})))
+	out := &strings.Builder{}
+	clusterClient.SetProgressOutput(out)
+
+	_, _, err := clusterClient.ListNodes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, out, clusterClient.ProgressOutput())
+	assert.Contains(t, out.String(), "✓ Found 1 nodes")
+}
+
+func TestClient_GetImageIndexFromNodes_TagDigests(t *testing.T) {
+	ctx := context.Background()
+	digestA := "sha256:" + strings.Repeat("a", 64)
+	digestB := "sha256:" + strings.Repeat("b", 64)
+
+	nodes := make([]runtime.Object, 0, 2)
+	for i, digest := range []string{digestA, digestB} {
+		node := createTestNode(fmt.Sprintf("node%d", i+1), nil)
+		node.Status.Images = []corev1.ContainerImage{{
+			Names:     []string{"docker.io/acme/app@" + digest, "docker.io/acme/app:stable"},
+			SizeBytes: 100000000,
+		}}
+		nodes = append(nodes, node)
+	}
+	pods := []types.Pod{{Name: "web", Namespace: "default", Images: []string{"acme/app:stable"}}}
+
+	tests := []struct {
+		name        string
+		tagDigests  bool
+		expectDrift int
+	}{
+		{name: "tag digests indexed", tagDigests: true, expectDrift: 1},
+		{name: "tag digests skipped", tagDigests: false, expectDrift: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, _, err := NewClient(kubernetes.NewFakeClient(nodes...)).GetImageIndexFromNodes(ctx, tt.tagDigests)
+			require.NoError(t, err)
+			assert.Len(t, index.Sizes, 1)
+			assert.Len(t, types.DetectTagDrift(pods, index), tt.expectDrift)
+		})
+	}
+}
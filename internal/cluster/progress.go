@@ -0,0 +1,93 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/briandowns/spinner"
+)
+
+// progress shows a single spinner for all in-flight queries of a client, so
+// queries running concurrently don't draw over each other
+type progress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	spinner *spinner.Spinner
+	tasks   []*progressTask
+}
+
+// progressTask is a single query shown by the spinner
+type progressTask struct {
+	p       *progress
+	message string
+}
+
+// newProgress creates a progress display writing to w
+func newProgress(w io.Writer) *progress {
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(w))
+	_ = s.Color("cyan")
+	return &progress{w: w, spinner: s}
+}
+
+// start adds a task to the spinner
+func (p *progress) start(message string) *progressTask {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t := &progressTask{p: p, message: message}
+	p.tasks = append(p.tasks, t)
+	p.render()
+	return t
+}
+
+// render shows the messages of all running tasks, and stops the spinner when
+// none are left. Callers must hold p.mu.
+func (p *progress) render() {
+	if len(p.tasks) == 0 {
+		p.spinner.Stop()
+		return
+	}
+
+	messages := make([]string, len(p.tasks))
+	for i, t := range p.tasks {
+		messages[i] = t.message
+	}
+	p.spinner.Lock()
+	p.spinner.Suffix = " " + strings.Join(messages, "; ")
+	p.spinner.Unlock()
+	p.spinner.Start()
+}
+
+// update replaces the message of the task
+func (t *progressTask) update(message string) {
+	t.p.mu.Lock()
+	defer t.p.mu.Unlock()
+
+	t.message = message
+	t.p.render()
+}
+
+// done removes the task from the spinner and prints its result line. An empty
+// format removes the task without printing anything, e.g. when the query failed.
+func (t *progressTask) done(format string, args ...interface{}) {
+	p := t.p
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, task := range p.tasks {
+		if task == t {
+			p.tasks = append(p.tasks[:i], p.tasks[i+1:]...)
+			break
+		}
+	}
+
+	// Clear the spinner line before printing, then resume for the remaining tasks
+	p.spinner.Stop()
+	if format != "" {
+		fmt.Fprintf(p.w, format, args...)
+	}
+	p.render()
+}
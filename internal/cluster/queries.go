@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// maxNamespaceQueries bounds the number of namespaces whose pods are listed at once
+const maxNamespaceQueries = 8
+
+// RunQueries runs the queries concurrently and waits for all of them to finish.
+// The first failure cancels the context passed to the others, and the errors of
+// all failed queries are joined.
+func RunQueries(ctx context.Context, queries ...func(ctx context.Context) error) error {
+	return runQueries(ctx, 0, queries)
+}
+
+// runQueries runs the queries concurrently, at most limit at a time when limit is
+// positive. See RunQueries.
+func runQueries(ctx context.Context, limit int, queries []func(ctx context.Context) error) error {
+	queryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var sem chan struct{}
+	if limit > 0 {
+		sem = make(chan struct{}, limit)
+	}
+
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		wg.Add(1)
+		go func(i int, query func(ctx context.Context) error) {
+			defer wg.Done()
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-queryCtx.Done():
+					errs[i] = queryCtx.Err()
+					return
+				}
+			}
+			if err := query(queryCtx); err != nil {
+				errs[i] = err
+				cancel()
+			}
+		}(i, query)
+	}
+	wg.Wait()
+
+	// Queries cancelled because another one failed only report the cancellation,
+	// which would hide the actual cause
+	var failed []error
+	var cancelled error
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+			if cancelled == nil {
+				cancelled = err
+			}
+		default:
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) == 0 {
+		return cancelled
+	}
+	return errors.Join(failed...)
+}
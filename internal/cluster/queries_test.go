@@ -0,0 +1,106 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunQueries(t *testing.T) {
+	errPods := errors.New("pods unavailable")
+	errNodes := errors.New("nodes unavailable")
+
+	// waitForCancel blocks until the query is cancelled by a failing sibling
+	waitForCancel := func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("query was not cancelled")
+		}
+	}
+
+	tests := []struct {
+		name        string
+		queries     []func(ctx context.Context) error
+		expectError []error
+	}{
+		{
+			name: "all queries succeed",
+			queries: []func(ctx context.Context) error{
+				func(ctx context.Context) error { return nil },
+				func(ctx context.Context) error { return nil },
+			},
+		},
+		{
+			name: "failure cancels the other queries",
+			queries: []func(ctx context.Context) error{
+				func(ctx context.Context) error { return errPods },
+				waitForCancel,
+			},
+			expectError: []error{errPods},
+		},
+		{
+			name: "errors of all failed queries are joined",
+			queries: []func(ctx context.Context) error{
+				func(ctx context.Context) error { return errPods },
+				func(ctx context.Context) error { return errNodes },
+			},
+			expectError: []error{errPods, errNodes},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RunQueries(context.Background(), tt.queries...)
+			if len(tt.expectError) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tt.expectError {
+				assert.ErrorIs(t, err, expected)
+			}
+			// Cancellation of the other queries is not reported as a failure
+			assert.NotErrorIs(t, err, context.Canceled)
+		})
+	}
+}
+
+func TestRunQueries_CancelledByCaller(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := RunQueries(ctx, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRunQueries_Limit(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	queries := make([]func(ctx context.Context) error, 10)
+	for i := range queries {
+		queries[i] = func(ctx context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+	}
+
+	require.NoError(t, runQueries(context.Background(), 3, queries))
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	assert.Positive(t, maxRunning.Load())
+}
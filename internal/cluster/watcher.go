@@ -0,0 +1,192 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// Watcher keeps pods and nodes up to date with shared informers and signals when
+// a change affects the image analysis. Replica sets and jobs are watched too when
+// pods need to be attributed to their top-level workloads.
+type Watcher struct {
+	k8sClient     kubernetes.Interface
+	filter        types.NamespaceFilter
+	namespace     string
+	labelSelector string
+	withOwners    bool
+
+	pods        cache.SharedIndexInformer
+	nodes       cache.SharedIndexInformer
+	replicaSets cache.SharedIndexInformer
+	jobs        cache.SharedIndexInformer
+	changed     chan struct{}
+}
+
+// NewWatcher creates a watcher for the pods in the namespaces selected by the filter
+// matching the label selector, and all nodes. The filter must not have a selector;
+// see Client.ResolveNamespaces. withOwners also watches replica sets and jobs for
+// workload attribution.
+func NewWatcher(k8sClient kubernetes.Interface, filter types.NamespaceFilter, labelSelector string, withOwners bool) *Watcher {
+	// A single namespace is watched directly; otherwise all namespaces are
+	// watched and pods outside the filter are ignored
+	namespace := ""
+	if len(filter.Include) == 1 {
+		namespace = filter.Include[0]
+	}
+	return &Watcher{
+		k8sClient:     k8sClient,
+		filter:        filter,
+		namespace:     namespace,
+		labelSelector: labelSelector,
+		withOwners:    withOwners,
+		changed:       make(chan struct{}, 1),
+	}
+}
+
+// Start starts the informers and waits for their caches to sync. The informers
+// stop when the context is cancelled.
+func (w *Watcher) Start(ctx context.Context) error {
+	w.pods = newInformer(&corev1.Pod{},
+		func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.LabelSelector = w.labelSelector
+			return w.k8sClient.ListPods(ctx, w.namespace, opts)
+		},
+		func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.LabelSelector = w.labelSelector
+			return w.k8sClient.WatchPods(ctx, w.namespace, opts)
+		})
+	w.nodes = newInformer(&corev1.Node{},
+		func(opts metav1.ListOptions) (runtime.Object, error) {
+			return w.k8sClient.ListNodes(ctx, opts)
+		},
+		func(opts metav1.ListOptions) (watch.Interface, error) {
+			return w.k8sClient.WatchNodes(ctx, opts)
+		})
+	informers := []cache.SharedIndexInformer{w.pods, w.nodes}
+
+	if w.withOwners {
+		w.replicaSets = newInformer(&appsv1.ReplicaSet{},
+			func(opts metav1.ListOptions) (runtime.Object, error) {
+				return w.k8sClient.ListReplicaSets(ctx, w.namespace, opts)
+			},
+			func(opts metav1.ListOptions) (watch.Interface, error) {
+				return w.k8sClient.WatchReplicaSets(ctx, w.namespace, opts)
+			})
+		w.jobs = newInformer(&batchv1.Job{},
+			func(opts metav1.ListOptions) (runtime.Object, error) {
+				return w.k8sClient.ListJobs(ctx, w.namespace, opts)
+			},
+			func(opts metav1.ListOptions) (watch.Interface, error) {
+				return w.k8sClient.WatchJobs(ctx, w.namespace, opts)
+			})
+		informers = append(informers, w.replicaSets, w.jobs)
+	}
+
+	// Pod and node status is updated far more often than the fields the analysis
+	// reads, so updates only signal a change when those fields differ
+	handlers := map[cache.SharedIndexInformer]func(oldObj, newObj interface{}) bool{
+		w.pods: func(oldObj, newObj interface{}) bool {
+			if !w.filter.Matches(newObj.(*corev1.Pod).Namespace) {
+				return false
+			}
+			return !reflect.DeepEqual(types.FromK8sPod(oldObj.(*corev1.Pod)), types.FromK8sPod(newObj.(*corev1.Pod)))
+		},
+		w.nodes: func(oldObj, newObj interface{}) bool {
+			return !equality.Semantic.DeepEqual(oldObj.(*corev1.Node).Status.Images, newObj.(*corev1.Node).Status.Images)
+		},
+	}
+	for _, informer := range informers {
+		updated := handlers[informer]
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { w.notify() },
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if updated == nil || updated(oldObj, newObj) {
+					w.notify()
+				}
+			},
+			DeleteFunc: func(obj interface{}) { w.notify() },
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add event handler: %w", err)
+		}
+	}
+
+	hasSynced := make([]cache.InformerSynced, len(informers))
+	for i, informer := range informers {
+		go informer.Run(ctx.Done())
+		hasSynced[i] = informer.HasSynced
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), hasSynced...) {
+		return fmt.Errorf("failed to sync informer caches: %w", ctx.Err())
+	}
+
+	return nil
+}
+
+// Changed returns a channel that receives a value after the watched objects
+// change. Changes made while a previous value is unread are coalesced.
+func (w *Watcher) Changed() <-chan struct{} {
+	return w.changed
+}
+
+// Snapshot returns the current contents of the informer caches. The objects are
+// shared with the caches and must not be modified.
+func (w *Watcher) Snapshot() *kubernetes.Snapshot {
+	snapshot := &kubernetes.Snapshot{}
+	for _, obj := range w.pods.GetStore().List() {
+		if pod := obj.(*corev1.Pod); w.filter.Matches(pod.Namespace) {
+			snapshot.Pods = append(snapshot.Pods, *pod)
+		}
+	}
+	for _, obj := range w.nodes.GetStore().List() {
+		snapshot.Nodes = append(snapshot.Nodes, *obj.(*corev1.Node))
+	}
+	if w.withOwners {
+		for _, obj := range w.replicaSets.GetStore().List() {
+			snapshot.ReplicaSets = append(snapshot.ReplicaSets, *obj.(*appsv1.ReplicaSet))
+		}
+		for _, obj := range w.jobs.GetStore().List() {
+			snapshot.Jobs = append(snapshot.Jobs, *obj.(*batchv1.Job))
+		}
+	}
+	return snapshot
+}
+
+// notify signals a change without blocking
+func (w *Watcher) notify() {
+	select {
+	case w.changed <- struct{}{}:
+	default:
+	}
+}
+
+// newInformer creates an informer for objects of the given type that drops managed
+// fields before caching objects, since the analysis never reads them
+func newInformer(objType runtime.Object, list cache.ListFunc, watchFunc cache.WatchFunc) cache.SharedIndexInformer {
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{ListFunc: list, WatchFunc: watchFunc},
+		objType,
+		0, // No resync; every change arrives as a watch event
+		cache.Indexers{},
+	)
+	_ = informer.SetTransform(func(obj interface{}) (interface{}, error) {
+		if accessor, ok := obj.(metav1.Object); ok {
+			accessor.SetManagedFields(nil)
+		}
+		return obj, nil
+	})
+	return informer
+}
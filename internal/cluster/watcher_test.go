@@ -0,0 +1,127 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// waitForWatches waits until the informers have started watching the given
+// resources, so objects created afterwards are delivered as watch events
+func waitForWatches(t *testing.T, client kubernetes.Interface, resources ...string) {
+	t.Helper()
+	clientset := client.(*kubernetes.FakeClient).Clientset()
+	require.Eventually(t, func() bool {
+		watching := make(map[string]bool)
+		for _, action := range clientset.Actions() {
+			if watchAction, ok := action.(k8stesting.WatchAction); ok {
+				watching[watchAction.GetResource().Resource] = true
+			}
+		}
+		for _, resource := range resources {
+			if !watching[resource] {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// waitForChange waits for the watcher to signal a change
+func waitForChange(t *testing.T, w *Watcher) {
+	t.Helper()
+	select {
+	case <-w.Changed():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a change")
+	}
+}
+
+// drainChanges discards a pending change signal
+func drainChanges(w *Watcher) {
+	select {
+	case <-w.Changed():
+	default:
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := kubernetes.NewFakeClient(
+		createTestPod("pod1", "default", "nginx:1.21"),
+		createTestPod("pod2", "other", "redis:6.2"),
+		createTestNode("node1", map[string]int64{"nginx:1.21": 100}),
+	)
+	clientset := client.(*kubernetes.FakeClient).Clientset()
+
+	w := NewWatcher(client, types.NamespaceFilter{Include: []string{"default"}}, "", false)
+	require.NoError(t, w.Start(ctx))
+	waitForWatches(t, client, "pods", "nodes")
+
+	snapshot := w.Snapshot()
+	require.Len(t, snapshot.Pods, 1, "only pods in the watched namespace are cached")
+	assert.Equal(t, "pod1", snapshot.Pods[0].Name)
+	require.Len(t, snapshot.Nodes, 1)
+	assert.Empty(t, snapshot.ReplicaSets)
+
+	// A new pod is a change
+	drainChanges(w)
+	_, err := clientset.CoreV1().Pods("default").Create(ctx, createTestPod("pod3", "default", "redis:6.2"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	waitForChange(t, w)
+	assert.Len(t, w.Snapshot().Pods, 2)
+
+	// A node status update that leaves the images alone is not a change
+	node, err := clientset.CoreV1().Nodes().Get(ctx, "node1", metav1.GetOptions{})
+	require.NoError(t, err)
+	node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	_, err = clientset.CoreV1().Nodes().UpdateStatus(ctx, node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// A node pulling an image is a change
+	node.Status.Images = append(node.Status.Images, corev1.ContainerImage{Names: []string{"redis:6.2"}, SizeBytes: 50})
+	_, err = clientset.CoreV1().Nodes().UpdateStatus(ctx, node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	waitForChange(t, w)
+	assert.Eventually(t, func() bool {
+		return len(w.Snapshot().Nodes[0].Status.Images) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWatcher_WithOwners(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := kubernetes.NewFakeClient(&appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default"},
+	})
+	w := NewWatcher(client, types.NamespaceFilter{}, "", true)
+	require.NoError(t, w.Start(ctx))
+
+	snapshot := w.Snapshot()
+	require.Len(t, snapshot.ReplicaSets, 1)
+	assert.Equal(t, "web-abc", snapshot.ReplicaSets[0].Name)
+	assert.Empty(t, snapshot.Jobs)
+}
+
+func TestWatcher_StartCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := NewWatcher(kubernetes.NewSnapshotClient(&kubernetes.Snapshot{}), types.NamespaceFilter{}, "", false)
+	err := w.Start(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to sync informer caches")
+}
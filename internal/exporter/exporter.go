@@ -0,0 +1,83 @@
+package exporter
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// contentType is the media type of the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter serves the results of the most recent analysis as Prometheus metrics.
+// Analyses run on their own schedule and scrapes return the last results, so a
+// scrape never waits on the cluster.
+type Exporter struct {
+	mu          sync.RWMutex
+	metrics     []byte    // Metrics of the last successful analysis
+	lastSuccess time.Time // Time of the last successful analysis
+	runs        int       // Number of analyses run
+	failures    int       // Number of analyses that failed
+}
+
+// NewExporter creates an exporter with no results
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// Update replaces the served metrics with the results of a successful analysis
+func (e *Exporter) Update(images *types.ImageAnalysis, nodes *types.NodeAnalysis, now time.Time) error {
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf, images, nodes); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metrics = buf.Bytes()
+	e.lastSuccess = now
+	e.runs++
+	return nil
+}
+
+// RecordFailure counts a failed analysis. The metrics of the last successful
+// analysis keep being served.
+func (e *Exporter) RecordFailure() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.runs++
+	e.failures++
+}
+
+// Ready reports whether an analysis has succeeded
+func (e *Exporter) Ready() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return !e.lastSuccess.IsZero()
+}
+
+// ServeHTTP writes the metrics of the last successful analysis, followed by the
+// exporter's own run metrics
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(e.metrics); err != nil {
+		return
+	}
+
+	mw := &metricWriter{w: w}
+	mw.family("runs_total", "counter", "Number of analyses run.")
+	mw.sample("runs_total", float64(e.runs))
+	mw.family("run_failures_total", "counter", "Number of analyses that failed.")
+	mw.sample("run_failures_total", float64(e.failures))
+	var lastSuccess float64
+	if !e.lastSuccess.IsZero() {
+		lastSuccess = float64(e.lastSuccess.Unix())
+	}
+	mw.gauge("last_success_timestamp_seconds", "Unix time of the last successful analysis.", lastSuccess)
+}
@@ -0,0 +1,174 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// metricPrefix namespaces every exported metric
+const metricPrefix = "analyze_images_"
+
+// label is a single metric label
+type label struct {
+	name  string
+	value string
+}
+
+// metricWriter writes metrics in the Prometheus text exposition format. The
+// first write error is kept and later writes are skipped.
+type metricWriter struct {
+	w   io.Writer
+	err error
+}
+
+// family writes the HELP and TYPE lines of a metric family
+func (mw *metricWriter) family(name, metricType, help string) {
+	mw.printf("# HELP %s%s %s\n", metricPrefix, name, help)
+	mw.printf("# TYPE %s%s %s\n", metricPrefix, name, metricType)
+}
+
+// sample writes a single sample of a metric family
+func (mw *metricWriter) sample(name string, value float64, labels ...label) {
+	var b strings.Builder
+	b.WriteString(metricPrefix)
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(l.name)
+			b.WriteString(`="`)
+			b.WriteString(escapeLabelValue(l.value))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	mw.printf("%s %s\n", b.String(), strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// gauge writes a metric family with a single unlabeled sample
+func (mw *metricWriter) gauge(name, help string, value float64) {
+	mw.family(name, "gauge", help)
+	mw.sample(name, value)
+}
+
+// printf writes formatted output unless an earlier write failed
+func (mw *metricWriter) printf(format string, args ...interface{}) {
+	if mw.err != nil {
+		return
+	}
+	_, mw.err = fmt.Fprintf(mw.w, format, args...)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and newlines in a label value
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// WriteMetrics writes the image and node analyses as Prometheus metrics. Either
+// analysis may be nil, in which case its metrics are left out.
+func WriteMetrics(w io.Writer, images *types.ImageAnalysis, nodes *types.NodeAnalysis) error {
+	mw := &metricWriter{w: w}
+
+	if images != nil {
+		writeImageMetrics(mw, images)
+	}
+	if nodes != nil {
+		writeNodeMetrics(mw, nodes)
+	}
+
+	return mw.err
+}
+
+// writeImageMetrics writes per-image sizes, per-namespace bytes, totals and timings
+func writeImageMetrics(mw *metricWriter, analysis *types.ImageAnalysis) {
+	// Sort by name so the output is stable between scrapes
+	images := make([]types.Image, 0, len(analysis.Images))
+	var inaccessible int
+	for _, img := range analysis.Images {
+		if img.Inaccessible {
+			inaccessible++
+			continue
+		}
+		images = append(images, img)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Name < images[j].Name
+	})
+
+	mw.family("image_size_bytes", "gauge", "Size of the container image in bytes.")
+	for _, img := range images {
+		mw.sample("image_size_bytes", float64(img.Size), imageLabels(img)...)
+	}
+	mw.family("image_nodes", "gauge", "Number of nodes holding the container image.")
+	for _, img := range images {
+		mw.sample("image_nodes", float64(img.NodeCount), imageLabels(img)...)
+	}
+
+	if len(analysis.Namespaces) > 0 {
+		mw.family("namespace_image_bytes", "gauge", "Bytes of the images used by pods in the namespace, each image counted once.")
+		for _, ns := range analysis.Namespaces {
+			mw.sample("namespace_image_bytes", float64(ns.TotalBytes()), label{"namespace", ns.Namespace})
+		}
+		mw.family("namespace_unique_image_bytes", "gauge", "Bytes of the images used only by pods in the namespace.")
+		for _, ns := range analysis.Namespaces {
+			mw.sample("namespace_unique_image_bytes", float64(ns.UniqueBytes), label{"namespace", ns.Namespace})
+		}
+	}
+
+	mw.gauge("images", "Number of images analyzed.", float64(len(analysis.Images)))
+	mw.gauge("inaccessible_images", "Number of images referenced by pods but not found in node status.", float64(inaccessible))
+	mw.gauge("unique_bytes", "Bytes of all images, each image counted once.", float64(analysis.UniqueSize))
+	mw.gauge("total_bytes", "Bytes of all images, summed over the images analyzed.", float64(analysis.TotalSize))
+	mw.gauge("cluster_bytes", "Bytes of all images across all nodes, counting every copy.", float64(analysis.ClusterSize))
+
+	if perf := analysis.Performance; perf != nil {
+		mw.family("analysis_duration_seconds", "gauge", "Duration of each phase of the last image analysis.")
+		mw.sample("analysis_duration_seconds", perf.PodQueryTime.Seconds(), label{"phase", "pod_query"})
+		mw.sample("analysis_duration_seconds", perf.NodeQueryTime.Seconds(), label{"phase", "node_query"})
+		mw.sample("analysis_duration_seconds", perf.OwnerQueryTime.Seconds(), label{"phase", "owner_query"})
+		mw.sample("analysis_duration_seconds", perf.QueryTime.Seconds(), label{"phase", "query"})
+		mw.sample("analysis_duration_seconds", perf.ImageAnalysisTime.Seconds(), label{"phase", "image_analysis"})
+		mw.sample("analysis_duration_seconds", perf.TotalTime.Seconds(), label{"phase", "total"})
+		mw.gauge("images_processed", "Number of images processed by the last image analysis.", float64(perf.ImagesProcessed))
+	}
+}
+
+// writeNodeMetrics writes per-node image bytes
+func writeNodeMetrics(mw *metricWriter, analysis *types.NodeAnalysis) {
+	nodes := make([]types.NodeUsage, len(analysis.Nodes))
+	copy(nodes, analysis.Nodes)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	mw.family("node_image_bytes", "gauge", "Bytes of the images cached on the node.")
+	for _, node := range nodes {
+		mw.sample("node_image_bytes", float64(node.TotalBytes), label{"node", node.Name})
+	}
+	mw.family("node_unreferenced_image_bytes", "gauge", "Bytes of the images cached on the node that no pod scheduled to the node references.")
+	for _, node := range nodes {
+		mw.sample("node_unreferenced_image_bytes", float64(node.UnreferencedBytes), label{"node", node.Name})
+	}
+	mw.family("node_images", "gauge", "Number of images cached on the node.")
+	for _, node := range nodes {
+		mw.sample("node_images", float64(node.ImageCount), label{"node", node.Name})
+	}
+}
+
+// imageLabels returns the labels identifying an image
+func imageLabels(img types.Image) []label {
+	return []label{
+		{"image", img.Name},
+		{"registry", img.Registry},
+		{"repository", img.Repository},
+		{"tag", img.Tag},
+	}
+}
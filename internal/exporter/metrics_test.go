@@ -0,0 +1,110 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+func testAnalyses() (*types.ImageAnalysis, *types.NodeAnalysis) {
+	nginx := types.NewImage("nginx:1.21", 100)
+	nginx.SetNodes([]string{"node1", "node2"})
+	images := &types.ImageAnalysis{
+		Images:      []types.Image{*nginx, *types.NewInaccessibleImage("private/image:latest")},
+		TotalSize:   100,
+		UniqueSize:  100,
+		ClusterSize: 200,
+		Namespaces:  []types.NamespaceUsage{{Namespace: "default", ImageCount: 1, UniqueBytes: 60, SharedBytes: 40}},
+		Performance: &types.PerformanceMetrics{
+			PodQueryTime:    250 * time.Millisecond,
+			TotalTime:       2 * time.Second,
+			ImagesProcessed: 2,
+		},
+	}
+	nodes := &types.NodeAnalysis{
+		Nodes: []types.NodeUsage{
+			{Name: "node2", ImageCount: 1, TotalBytes: 100},
+			{Name: "node1", ImageCount: 2, TotalBytes: 150, UnreferencedBytes: 50},
+		},
+	}
+	return images, nodes
+}
+
+func TestWriteMetrics(t *testing.T) {
+	images, nodes := testAnalyses()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMetrics(&buf, images, nodes))
+	output := buf.String()
+
+	for _, want := range []string{
+		"# HELP analyze_images_image_size_bytes Size of the container image in bytes.\n# TYPE analyze_images_image_size_bytes gauge\n",
+		`analyze_images_image_size_bytes{image="nginx:1.21",registry="docker.io",repository="library/nginx",tag="1.21"} 100` + "\n",
+		`analyze_images_image_nodes{image="nginx:1.21",registry="docker.io",repository="library/nginx",tag="1.21"} 2` + "\n",
+		`analyze_images_namespace_image_bytes{namespace="default"} 100` + "\n",
+		`analyze_images_namespace_unique_image_bytes{namespace="default"} 60` + "\n",
+		"analyze_images_inaccessible_images 1\n",
+		"analyze_images_unique_bytes 100\n",
+		"analyze_images_total_bytes 100\n",
+		"analyze_images_cluster_bytes 200\n",
+		`analyze_images_analysis_duration_seconds{phase="pod_query"} 0.25` + "\n",
+		`analyze_images_analysis_duration_seconds{phase="total"} 2` + "\n",
+		"analyze_images_images_processed 2\n",
+		"analyze_images_node_image_bytes{node=\"node1\"} 150\nanalyze_images_node_image_bytes{node=\"node2\"} 100\n",
+		`analyze_images_node_unreferenced_image_bytes{node="node1"} 50` + "\n",
+	} {
+		assert.Contains(t, output, want)
+	}
+	assert.NotContains(t, output, "private/image", "inaccessible images have no size")
+}
+
+func TestWriteMetrics_NilAnalyses(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMetrics(&buf, nil, nil))
+	assert.Empty(t, buf.String())
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	assert.Equal(t, `a\\b\"c\nd`, escapeLabelValue("a\\b\"c\nd"))
+}
+
+func TestExporter_ServeHTTP(t *testing.T) {
+	exp := NewExporter()
+	assert.False(t, exp.Ready())
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		exp.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentType, rec.Header().Get("Content-Type"))
+		body, err := io.ReadAll(rec.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	// Before the first analysis only the run metrics are served
+	output := scrape()
+	assert.Contains(t, output, "analyze_images_runs_total 0\n")
+	assert.Contains(t, output, "analyze_images_last_success_timestamp_seconds 0\n")
+	assert.NotContains(t, output, "analyze_images_image_size_bytes")
+
+	images, nodes := testAnalyses()
+	require.NoError(t, exp.Update(images, nodes, time.Unix(1700000000, 0)))
+	exp.RecordFailure()
+	assert.True(t, exp.Ready())
+
+	// A failed analysis keeps the last results
+	output = scrape()
+	assert.Contains(t, output, "analyze_images_image_size_bytes{")
+	assert.Contains(t, output, "analyze_images_runs_total 2\n")
+	assert.Contains(t, output, "analyze_images_run_failures_total 1\n")
+	assert.Contains(t, output, "analyze_images_last_success_timestamp_seconds 1.7e+09\n")
+}
@@ -0,0 +1,158 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
+)
+
+// Columns supported by the CSV and TSV printers
+const (
+	ColumnName           = "name"
+	ColumnRegistry       = "registry"
+	ColumnRepository     = "repository"
+	ColumnTag            = "tag"
+	ColumnDigest         = "digest"
+	ColumnSize           = "size"
+	ColumnHumanSize      = "human-size"
+	ColumnNodeCount      = "node-count"
+	ColumnClusterSize    = "cluster-size"
+	ColumnInaccessible   = "inaccessible"
+	ColumnNamespaces     = "namespaces"
+	ColumnContainerTypes = "container-types"
+)
+
+// CSVColumns lists every supported column
+var CSVColumns = []string{
+	ColumnName, ColumnRegistry, ColumnRepository, ColumnTag, ColumnDigest, ColumnSize,
+	ColumnHumanSize, ColumnNodeCount, ColumnClusterSize, ColumnInaccessible, ColumnNamespaces,
+	ColumnContainerTypes,
+}
+
+// DefaultCSVColumns lists the columns printed when none are selected
+var DefaultCSVColumns = []string{
+	ColumnName, ColumnRegistry, ColumnRepository, ColumnTag, ColumnSize, ColumnHumanSize, ColumnNodeCount, ColumnInaccessible,
+}
+
+// ValidateCSVColumns checks that every column is supported
+func ValidateCSVColumns(columns []string) error {
+	for _, column := range columns {
+		valid := false
+		for _, supported := range CSVColumns {
+			if column == supported {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid column %q: must be one of %s", column, strings.Join(CSVColumns, ", "))
+		}
+	}
+	return nil
+}
+
+// CSVPrinter formats output as delimiter-separated values, one row per image
+type CSVPrinter struct {
+	comma   rune
+	columns []string
+	sortBy  string
+}
+
+// NewCSVPrinter creates a printer for comma-separated values with the given columns
+// (DefaultCSVColumns if empty)
+func NewCSVPrinter(columns []string) *CSVPrinter {
+	return newDelimitedPrinter(',', columns)
+}
+
+// NewTSVPrinter creates a printer for tab-separated values with the given columns
+// (DefaultCSVColumns if empty)
+func NewTSVPrinter(columns []string) *CSVPrinter {
+	return newDelimitedPrinter('\t', columns)
+}
+
+// newDelimitedPrinter creates a printer using the given field delimiter
+func newDelimitedPrinter(comma rune, columns []string) *CSVPrinter {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	return &CSVPrinter{
+		comma:   comma,
+		columns: columns,
+		sortBy:  types.SortBySize,
+	}
+}
+
+// SetSortBy sets the order of the rows ("size" or "cluster-size")
+func (cp *CSVPrinter) SetSortBy(sortBy string) {
+	cp.sortBy = sortBy
+}
+
+// Print writes a header row and one row per image to the provided writer
+func (cp *CSVPrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error {
+	if err := ValidateCSVColumns(cp.columns); err != nil {
+		return err
+	}
+
+	var images []types.Image
+	if cp.sortBy == types.SortByClusterSize {
+		images = analysis.GetTopImagesByClusterSize(len(analysis.Images))
+	} else {
+		images = analysis.GetTopImagesBySize(len(analysis.Images))
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = cp.comma
+	if err := writer.Write(cp.columns); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	row := make([]string, len(cp.columns))
+	for _, img := range images {
+		for i, column := range cp.columns {
+			row[i] = imageColumn(img, column)
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// imageColumn returns the value of a column for an image
+func imageColumn(img types.Image, column string) string {
+	switch column {
+	case ColumnName:
+		return img.Name
+	case ColumnRegistry:
+		return img.Registry
+	case ColumnRepository:
+		return img.Repository
+	case ColumnTag:
+		return img.Tag
+	case ColumnDigest:
+		return img.Digest
+	case ColumnSize:
+		return strconv.FormatInt(img.Size, 10)
+	case ColumnHumanSize:
+		return util.FormatBytes(img.Size)
+	case ColumnNodeCount:
+		return strconv.Itoa(img.NodeCount)
+	case ColumnClusterSize:
+		return strconv.FormatInt(img.ClusterSize, 10)
+	case ColumnInaccessible:
+		return strconv.FormatBool(img.Inaccessible)
+	case ColumnNamespaces:
+		return strings.Join(img.Namespaces, ";")
+	case ColumnContainerTypes:
+		return strings.Join(img.ContainerTypes, ";")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,87 @@
+package reporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+func TestCSVPrinter_Print(t *testing.T) {
+	analysis := &types.ImageAnalysis{
+		Images: []types.Image{
+			{Name: "redis:6.2", Size: 50000000, Registry: "docker.io", Repository: "library/redis", Tag: "6.2", NodeCount: 3, ClusterSize: 150000000},
+			{Name: "gcr.io/team/app:v1", Size: 100000000, Registry: "gcr.io", Repository: "team/app", Tag: "v1", NodeCount: 1, ClusterSize: 100000000, Namespaces: []string{"a", "b"}},
+			{Name: "private/image:latest", Inaccessible: true},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		printer  *CSVPrinter
+		sortBy   string
+		expected string
+	}{
+		{
+			name:    "default columns",
+			printer: NewCSVPrinter(nil),
+			expected: "name,registry,repository,tag,size,human-size,node-count,inaccessible\n" +
+				"gcr.io/team/app:v1,gcr.io,team/app,v1,100000000,95.4 MB,1,false\n" +
+				"redis:6.2,docker.io,library/redis,6.2,50000000,47.7 MB,3,false\n" +
+				"private/image:latest,,,,0,0 B,0,true\n",
+		},
+		{
+			name:    "selected columns sorted by cluster size",
+			printer: NewCSVPrinter([]string{"name", "cluster-size", "namespaces"}),
+			sortBy:  types.SortByClusterSize,
+			expected: "name,cluster-size,namespaces\n" +
+				"redis:6.2,150000000,\n" +
+				"gcr.io/team/app:v1,100000000,a;b\n" +
+				"private/image:latest,0,\n",
+		},
+		{
+			name:    "tab separated",
+			printer: NewTSVPrinter([]string{"name", "size"}),
+			expected: "name\tsize\n" +
+				"gcr.io/team/app:v1\t100000000\n" +
+				"redis:6.2\t50000000\n" +
+				"private/image:latest\t0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.sortBy != "" {
+				tt.printer.SetSortBy(tt.sortBy)
+			}
+
+			var buf bytes.Buffer
+			err := tt.printer.Print(&buf, analysis)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestCSVPrinter_Print_QuotesFields(t *testing.T) {
+	analysis := &types.ImageAnalysis{
+		Images: []types.Image{{Name: "odd,name", Size: 1}},
+	}
+
+	var buf bytes.Buffer
+	err := NewCSVPrinter([]string{"name"}).Print(&buf, analysis)
+	require.NoError(t, err)
+	assert.Equal(t, "name\n\"odd,name\"\n", buf.String())
+}
+
+func TestValidateCSVColumns(t *testing.T) {
+	assert.NoError(t, ValidateCSVColumns(CSVColumns))
+	assert.NoError(t, ValidateCSVColumns(nil))
+
+	err := ValidateCSVColumns([]string{"name", "owner"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid column "owner"`)
+}
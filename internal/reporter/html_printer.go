@@ -0,0 +1,252 @@
+package reporter
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
+)
+
+//go:embed templates/report.html
+var htmlReportTemplate string
+
+// htmlTemplate renders the self-contained HTML report. Styles, scripts and charts
+// are inlined so the output can be opened or shared without external assets.
+var htmlTemplate = template.Must(template.New("report").Parse(htmlReportTemplate))
+
+// Chart layout, in SVG user units
+const (
+	htmlChartLabelWidth = 200
+	htmlChartBarWidth   = 300
+	htmlChartRowHeight  = 22
+)
+
+// HTMLPrinter formats output as a single self-contained HTML page
+type HTMLPrinter struct {
+	sortBy string
+}
+
+// NewHTMLPrinter creates a new HTML printer
+func NewHTMLPrinter() *HTMLPrinter {
+	return &HTMLPrinter{sortBy: types.SortBySize}
+}
+
+// SetSortBy sets the initial order of the image table
+func (hp *HTMLPrinter) SetSortBy(sortBy string) {
+	hp.sortBy = sortBy
+}
+
+// htmlReport is the data rendered by the HTML template
+type htmlReport struct {
+	Title      string
+	SortBy     string
+	Summary    []htmlStat
+	Violations []types.PolicyViolation
+	TagHygiene *types.TagHygiene
+	TagDrift   []types.TagDrift
+	Charts     []htmlChart
+	Images     []htmlImage
+}
+
+// htmlStat is a single summary value
+type htmlStat struct {
+	Label string
+	Value string
+}
+
+// htmlChart is a horizontal bar chart
+type htmlChart struct {
+	Title        string
+	Height       int
+	FilterColumn string // Image table attribute ("namespaces" or "registry") holding the bar's filter value; empty if bars don't filter
+	Bars         []htmlBar
+}
+
+// htmlBar is a single bar of a chart, with its layout precomputed
+type htmlBar struct {
+	Label   string
+	Value   string
+	Tooltip string
+	Filter  string // Value the image table rows must hold exactly when the bar is clicked, so "prod" does not match "prod-canary"
+	Y       int
+	Width   int
+	TextX   int
+}
+
+// htmlImage is a single row of the image table
+type htmlImage struct {
+	Name             string
+	Registry         string
+	Tag              string
+	Size             int64
+	SizeLabel        string
+	NodeCount        int
+	ClusterSize      int64
+	ClusterSizeLabel string
+	Namespaces       string
+	NamespaceList    []string // Namespaces, matched exactly by the namespace chart
+	Inaccessible     bool
+}
+
+// Print writes the analysis as an HTML page to the provided writer
+func (hp *HTMLPrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error {
+	report := htmlReport{
+		Title:      "Container Image Analysis",
+		SortBy:     hp.sortBy,
+		Summary:    htmlSummary(analysis),
+		Violations: analysis.Violations,
+		TagHygiene: analysis.TagHygiene,
+		TagDrift:   analysis.TagDrift,
+		Charts:     htmlCharts(analysis),
+		Images:     hp.htmlImages(analysis),
+	}
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to write HTML: %w", err)
+	}
+	return nil
+}
+
+// htmlSummary returns the summary values of the analysis
+func htmlSummary(analysis *types.ImageAnalysis) []htmlStat {
+	var inaccessible int
+	for _, img := range analysis.Images {
+		if img.Inaccessible {
+			inaccessible++
+		}
+	}
+
+	summary := []htmlStat{
+		{Label: "Total Images", Value: strconv.Itoa(len(analysis.Images))},
+		{Label: "Total Size", Value: util.FormatBytes(analysis.TotalSize)},
+		{Label: "Cluster Size (all nodes)", Value: util.FormatBytes(analysis.ClusterSize)},
+		{Label: "Inaccessible Images", Value: strconv.Itoa(inaccessible)},
+	}
+	if perf := analysis.Performance; perf != nil {
+		summary = append(summary, htmlStat{Label: "Total Time", Value: perf.TotalTime.String()})
+	}
+	return summary
+}
+
+// htmlCharts returns the size histogram and the namespace and registry breakdowns
+func htmlCharts(analysis *types.ImageAnalysis) []htmlChart {
+	var charts []htmlChart
+
+	histogram := analysis.GenerateImageSizeHistogram(types.DefaultHistogramConfig())
+	if len(histogram.Bins) > 0 {
+		values := make([]int64, len(histogram.Bins))
+		bars := make([]htmlBar, len(histogram.Bins))
+		for i, bin := range histogram.Bins {
+			values[i] = int64(bin.Count)
+			bars[i] = htmlBar{
+				Label:   fmt.Sprintf("%s - %s", util.FormatBytes(int64(bin.Min)), util.FormatBytes(int64(bin.Max))),
+				Value:   strconv.Itoa(bin.Count),
+				Tooltip: strings.Join(bin.Items, "\n"),
+			}
+		}
+		charts = append(charts, newHTMLChart("Image Size Distribution", "", bars, values))
+	}
+
+	if len(analysis.Namespaces) > 0 {
+		values := make([]int64, len(analysis.Namespaces))
+		bars := make([]htmlBar, len(analysis.Namespaces))
+		for i, ns := range analysis.Namespaces {
+			values[i] = ns.TotalBytes()
+			bars[i] = htmlBar{
+				Label: ns.Namespace,
+				Value: util.FormatBytes(ns.TotalBytes()),
+				Tooltip: fmt.Sprintf("%s: %d images, %s unique, %s shared",
+					ns.Namespace, ns.ImageCount, util.FormatBytes(ns.UniqueBytes), util.FormatBytes(ns.SharedBytes)),
+				Filter: ns.Namespace,
+			}
+		}
+		charts = append(charts, newHTMLChart("Size by Namespace", "namespaces", bars, values))
+	}
+
+	registries := analysis.Registries
+	if len(registries) == 0 {
+		registries = types.AttributeRegistries(analysis.Images)
+	}
+	if len(registries) > 0 {
+		values := make([]int64, len(registries))
+		bars := make([]htmlBar, len(registries))
+		for i, reg := range registries {
+			values[i] = reg.ClusterBytes
+			bars[i] = htmlBar{
+				Label: reg.Name,
+				Value: util.FormatBytes(reg.ClusterBytes),
+				Tooltip: fmt.Sprintf("%s: %d images, %s each, %.1f%% of the cluster footprint",
+					reg.Name, reg.ImageCount, util.FormatBytes(reg.TotalBytes), reg.Share*100),
+				Filter: reg.Name,
+			}
+		}
+		charts = append(charts, newHTMLChart("Size by Registry", "registry", bars, values))
+	}
+
+	return charts
+}
+
+// newHTMLChart lays out the bars of a chart, scaling their width to the largest value.
+// Clicking a bar filters the image table on the given column, if any.
+func newHTMLChart(title, filterColumn string, bars []htmlBar, values []int64) htmlChart {
+	var maxValue int64
+	for _, v := range values {
+		if v > maxValue {
+			maxValue = v
+		}
+	}
+
+	for i := range bars {
+		width := 0
+		if maxValue > 0 {
+			width = int(values[i] * htmlChartBarWidth / maxValue)
+		}
+		bars[i].Y = i * htmlChartRowHeight
+		bars[i].Width = width
+		bars[i].TextX = htmlChartLabelWidth + width + 6
+	}
+
+	return htmlChart{
+		Title:        title,
+		Height:       len(bars) * htmlChartRowHeight,
+		FilterColumn: filterColumn,
+		Bars:         bars,
+	}
+}
+
+// htmlImages returns the rows of the image table in the configured order
+func (hp *HTMLPrinter) htmlImages(analysis *types.ImageAnalysis) []htmlImage {
+	var images []types.Image
+	if hp.sortBy == types.SortByClusterSize {
+		images = analysis.GetTopImagesByClusterSize(len(analysis.Images))
+	} else {
+		images = analysis.GetTopImagesBySize(len(analysis.Images))
+	}
+
+	rows := make([]htmlImage, len(images))
+	for i, img := range images {
+		row := htmlImage{
+			Name:             img.Name,
+			Registry:         img.Registry,
+			Tag:              img.Tag,
+			Size:             img.Size,
+			SizeLabel:        util.FormatBytes(img.Size),
+			NodeCount:        img.NodeCount,
+			ClusterSize:      img.ClusterSize,
+			ClusterSizeLabel: util.FormatBytes(img.ClusterSize),
+			Namespaces:       strings.Join(img.Namespaces, ", "),
+			NamespaceList:    img.Namespaces,
+			Inaccessible:     img.Inaccessible,
+		}
+		if img.Inaccessible {
+			row.SizeLabel = "INACCESSIBLE"
+			row.ClusterSizeLabel = "-"
+		}
+		rows[i] = row
+	}
+	return rows
+}
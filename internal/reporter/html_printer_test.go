@@ -0,0 +1,107 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+func TestHTMLPrinter_Print(t *testing.T) {
+	nginx := types.NewImage("nginx:1.21", 133000000)
+	nginx.SetNodes([]string{"node1", "node2"})
+	nginx.Namespaces = []string{"default"}
+	redis := types.NewImage("quay.io/redis:6.2", 50000000)
+	redis.SetNodes([]string{"node1"})
+	analysis := &types.ImageAnalysis{
+		Images:      []types.Image{*redis, *nginx, *types.NewInaccessibleImage("private/image:latest")},
+		TotalSize:   183000000,
+		UniqueSize:  183000000,
+		ClusterSize: 316000000,
+		Namespaces:  []types.NamespaceUsage{{Namespace: "default", ImageCount: 1, UniqueBytes: 133000000}},
+		Violations:  []types.PolicyViolation{{Rule: types.RuleLatestTag, Subject: "private/image:latest", Message: "uses <latest> tag"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewHTMLPrinter().Print(&buf, analysis))
+	output := buf.String()
+
+	assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"))
+	assert.Contains(t, output, "</html>")
+
+	// Self-contained: no external scripts, styles or images
+	assert.NotContains(t, output, "src=")
+	assert.NotContains(t, output, "<link")
+
+	// Summary, charts and violations
+	assert.Contains(t, output, "Total Images")
+	assert.Contains(t, output, "Image Size Distribution")
+	assert.Contains(t, output, "Size by Namespace")
+	assert.Contains(t, output, `data-filter="default" data-filter-column="namespaces"`)
+	assert.Contains(t, output, "Size by Registry")
+	assert.Contains(t, output, `data-filter="quay.io" data-filter-column="registry"`)
+	assert.Contains(t, output, "Policy Violations")
+	assert.Contains(t, output, "uses &lt;latest&gt; tag", "values should be escaped")
+
+	// Images are sorted by size, with raw values for client-side sorting
+	nginxRow := strings.Index(output, "<td>nginx:1.21</td>")
+	redisRow := strings.Index(output, "<td>quay.io/redis:6.2</td>")
+	require.NotEqual(t, -1, nginxRow)
+	require.NotEqual(t, -1, redisRow)
+	assert.Less(t, nginxRow, redisRow)
+	assert.Contains(t, output, `data-value="133000000"`)
+	assert.Contains(t, output, `<tr class="inaccessible"`)
+}
+
+func TestHTMLPrinter_SortByClusterSize(t *testing.T) {
+	small := types.NewImage("small:v1", 10)
+	small.SetNodes([]string{"node1", "node2", "node3"})
+	large := types.NewImage("large:v1", 20)
+	large.SetNodes([]string{"node1"})
+	analysis := &types.ImageAnalysis{Images: []types.Image{*large, *small}}
+
+	hp := NewHTMLPrinter()
+	hp.SetSortBy(types.SortByClusterSize)
+	var buf bytes.Buffer
+	require.NoError(t, hp.Print(&buf, analysis))
+	output := buf.String()
+
+	assert.Less(t, strings.Index(output, "<td>small:v1</td>"), strings.Index(output, "<td>large:v1</td>"))
+	assert.Contains(t, output, `class="desc">Cluster Size</th>`)
+	assert.NotContains(t, output, "Size by Namespace", "namespace chart needs namespace attribution")
+}
+
+func TestHTMLPrinter_NamespaceFilterIsExact(t *testing.T) {
+	web := types.NewImage("web:v1", 100)
+	web.Namespaces = []string{"prod", "prod-canary"}
+	canary := types.NewImage("web:v2", 100)
+	canary.Namespaces = []string{"prod-canary"}
+	analysis := &types.ImageAnalysis{
+		Images: []types.Image{*web, *canary},
+		Namespaces: []types.NamespaceUsage{
+			{Namespace: "prod", ImageCount: 1, SharedBytes: 100},
+			{Namespace: "prod-canary", ImageCount: 2, UniqueBytes: 100, SharedBytes: 100},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewHTMLPrinter().Print(&buf, analysis))
+	output := buf.String()
+
+	// Rows carry their namespaces as a list, so the "prod" bar only matches rows listing "prod"
+	assert.Contains(t, output, `data-filter="prod" data-filter-column="namespaces"`)
+	assert.Contains(t, output, `data-namespaces="prod prod-canary">`+"\n      <td>web:v1</td>")
+	assert.Contains(t, output, `data-namespaces="prod-canary">`+"\n      <td>web:v2</td>")
+	assert.Contains(t, output, "values.indexOf(barFilter.value) >= 0")
+}
+
+func TestHTMLPrinter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewHTMLPrinter().Print(&buf, &types.ImageAnalysis{}))
+	assert.NotContains(t, buf.String(), "Image Size Distribution")
+	assert.Contains(t, buf.String(), "Images")
+}
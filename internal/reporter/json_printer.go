@@ -8,7 +8,7 @@ import (
 	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
 )
 
-// JSONPrinter formats output as JSON
+// JSONPrinter formats output as JSON following the versioned report schema in pkg/types
 type JSONPrinter struct{}
 
 // NewJSONPrinter creates a new JSON printer
@@ -18,25 +18,21 @@ func NewJSONPrinter() *JSONPrinter {
 
 // Print writes the analysis as JSON to the provided writer
 func (jp *JSONPrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error {
-	// Create a structured report for JSON marshaling
-	report := struct {
-		Performance *types.PerformanceMetrics `json:"performance,omitempty"`
-		Summary     struct {
-			TotalImages int   `json:"totalImages"`
-			TotalSize   int64 `json:"totalSize"`
-			UniqueSize  int64 `json:"uniqueSize"`
-		} `json:"summary"`
-		Images []types.Image `json:"images"`
-	}{
-		Performance: analysis.Performance,
-		Images:      analysis.Images,
-	}
+	return writeJSON(w, types.NewImageReport(analysis))
+}
 
-	report.Summary.TotalImages = len(analysis.Images)
-	report.Summary.TotalSize = analysis.TotalSize
-	report.Summary.UniqueSize = analysis.UniqueSize
+// PrintNodes writes the per-node image footprint as JSON to the provided writer
+func (jp *JSONPrinter) PrintNodes(w io.Writer, analysis *types.NodeAnalysis) error {
+	return writeJSON(w, types.NewNodeReport(analysis))
+}
 
-	// Use json.NewEncoder to write directly to the writer
+// PrintUnusedImages writes the unused images cached on nodes as JSON to the provided writer
+func (jp *JSONPrinter) PrintUnusedImages(w io.Writer, analysis *types.UnusedImageAnalysis) error {
+	return writeJSON(w, types.NewUnusedImageReport(analysis))
+}
+
+// writeJSON encodes the report as indented JSON directly to the writer
+func writeJSON(w io.Writer, report interface{}) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(report); err != nil {
@@ -45,3 +41,8 @@ func (jp *JSONPrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error {
 
 	return nil
 }
+
+// PrintDiff writes the differences between two analyses as JSON to the provided writer
+func (jp *JSONPrinter) PrintDiff(w io.Writer, diff *types.ImageDiff) error {
+	return writeJSON(w, types.NewImageDiffReport(diff))
+}
@@ -3,6 +3,7 @@ package reporter
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ func TestJSONPrinter_Print(t *testing.T) {
 		wantTotalImages   int
 		wantTotalSize     int64
 		wantUniqueSize    int64
+		wantClusterSize   int64
 		wantPerformance   bool
 		wantImagesNotNull bool
 	}{
@@ -30,8 +32,9 @@ func TestJSONPrinter_Print(t *testing.T) {
 					{Name: "nginx:1.21", Size: 133000000, Registry: "docker.io", Tag: "1.21"},
 					{Name: "redis:6.2", Size: 110000000, Registry: "docker.io", Tag: "6.2"},
 				},
-				TotalSize:  243000000,
-				UniqueSize: 200000000,
+				TotalSize:   243000000,
+				UniqueSize:  200000000,
+				ClusterSize: 376000000,
 				Performance: &types.PerformanceMetrics{
 					ImagesProcessed: 2,
 					TotalTime:       1500 * time.Millisecond,
@@ -41,6 +44,7 @@ func TestJSONPrinter_Print(t *testing.T) {
 			wantTotalImages:   2,
 			wantTotalSize:     243000000,
 			wantUniqueSize:    200000000,
+			wantClusterSize:   376000000,
 			wantPerformance:   true,
 			wantImagesNotNull: true,
 		},
@@ -145,6 +149,10 @@ func TestJSONPrinter_Print(t *testing.T) {
 			require.True(t, ok, "summary should have 'uniqueSize'")
 			assert.Equal(t, float64(tt.wantUniqueSize), uniqueSize, "uniqueSize should match")
 
+			clusterSize, ok := summaryMap["clusterSize"]
+			require.True(t, ok, "summary should have 'clusterSize'")
+			assert.Equal(t, float64(tt.wantClusterSize), clusterSize, "clusterSize should match")
+
 			// Check performance
 			performance, hasPerformance := result["performance"]
 			if tt.wantPerformance {
@@ -154,8 +162,8 @@ func TestJSONPrinter_Print(t *testing.T) {
 				perfMap, ok := performance.(map[string]interface{})
 				require.True(t, ok, "performance should be an object")
 
-				_, hasImagesProcessed := perfMap["ImagesProcessed"]
-				assert.True(t, hasImagesProcessed, "performance should have 'ImagesProcessed' field")
+				_, hasImagesProcessed := perfMap["imagesProcessed"]
+				assert.True(t, hasImagesProcessed, "performance should have 'imagesProcessed' field")
 			} else if hasPerformance {
 				// When performance is nil, it should be omitted or null
 				assert.Nil(t, performance, "performance should be nil when not provided")
@@ -192,9 +200,37 @@ func TestJSONPrinter_Print_InaccessibleImage(t *testing.T) {
 
 	image, ok := images[0].(map[string]interface{})
 	require.True(t, ok)
-	assert.Equal(t, "private/image:latest", image["Name"])
-	assert.Equal(t, true, image["Inaccessible"])
-	assert.Equal(t, float64(0), image["Size"])
+	assert.Equal(t, "private/image:latest", image["name"])
+	assert.Equal(t, true, image["inaccessible"])
+	assert.Equal(t, float64(0), image["size"])
+}
+
+func TestJSONPrinter_Print_ReferenceFields(t *testing.T) {
+	analysis := &types.ImageAnalysis{
+		Images: []types.Image{
+			*types.NewImage("registry:443/team/app@sha256:"+strings.Repeat("c", 64), 100000000),
+		},
+		TotalSize: 100000000,
+	}
+
+	var buf bytes.Buffer
+	err := NewJSONPrinter().Print(&buf, analysis)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &result)
+	require.NoError(t, err)
+
+	images, ok := result["images"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, images, 1)
+
+	image, ok := images[0].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "registry:443", image["registry"])
+	assert.Equal(t, "team/app", image["repository"])
+	assert.Equal(t, "", image["tag"])
+	assert.Equal(t, "sha256:"+strings.Repeat("c", 64), image["digest"])
 }
 
 func TestJSONPrinter_Print_CompletePerformanceMetrics(t *testing.T) {
@@ -231,9 +267,175 @@ func TestJSONPrinter_Print_CompletePerformanceMetrics(t *testing.T) {
 	require.True(t, ok, "performance should be present")
 
 	// Verify all performance fields are present
-	assert.Equal(t, float64(1), performance["ImagesProcessed"])
-	assert.Equal(t, float64(0), performance["ImagesFailed"])
-	assert.Equal(t, float64(0), performance["ImagesInaccessible"])
-	assert.Equal(t, float64(10), performance["CacheHits"])
-	assert.Equal(t, float64(5), performance["CacheMisses"])
+	assert.Equal(t, float64(1), performance["imagesProcessed"])
+	assert.Equal(t, float64(0), performance["imagesFailed"])
+	assert.Equal(t, float64(0), performance["imagesInaccessible"])
+	assert.Equal(t, float64(10), performance["cacheHits"])
+	assert.Equal(t, float64(5), performance["cacheMisses"])
+}
+
+func TestJSONPrinter_PrintNodes(t *testing.T) {
+	analysis := &types.NodeAnalysis{
+		Nodes: []types.NodeUsage{
+			{Name: "node1", ImageCount: 2, TotalBytes: 400000000, UnreferencedBytes: 300000000, EphemeralStorageAllocatable: 1000000000},
+			{Name: "node2", ImageCount: 1, TotalBytes: 50000000},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := NewJSONPrinter().PrintNodes(&buf, analysis)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &result)
+	require.NoError(t, err)
+
+	summary, ok := result["summary"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(2), summary["totalNodes"])
+	assert.Equal(t, float64(450000000), summary["totalSize"])
+	assert.Equal(t, float64(300000000), summary["unreferencedSize"])
+
+	nodes, ok := result["nodes"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, nodes, 2)
+	node, ok := nodes[0].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "node1", node["name"])
+	assert.Equal(t, float64(1000000000), node["ephemeralStorageAllocatable"])
+}
+
+func TestJSONPrinter_PrintNodes_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewJSONPrinter().PrintNodes(&buf, &types.NodeAnalysis{})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &result)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{}, result["nodes"])
+}
+
+func TestJSONPrinter_PrintUnusedImages(t *testing.T) {
+	analysis := &types.UnusedImageAnalysis{
+		Nodes: []types.NodeUnusedImages{
+			{
+				Node:             "node1",
+				Images:           []types.UnusedImage{{Name: "old-app:v1", Size: 300000000}},
+				ReclaimableBytes: 300000000,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := NewJSONPrinter().PrintUnusedImages(&buf, analysis)
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &result)
+	require.NoError(t, err)
+
+	summary, ok := result["summary"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, false, summary["clusterWide"])
+	assert.Equal(t, float64(1), summary["totalImages"])
+	assert.Equal(t, float64(300000000), summary["reclaimableSize"])
+
+	nodes, ok := result["nodes"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, nodes, 1)
+	node, ok := nodes[0].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "node1", node["node"])
+}
+
+func TestJSONPrinter_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		print    func(w *bytes.Buffer) error
+		wantKind string
+	}{
+		{
+			name:     "image report",
+			print:    func(w *bytes.Buffer) error { return NewJSONPrinter().Print(w, &types.ImageAnalysis{}) },
+			wantKind: types.ImageReportKind,
+		},
+		{
+			name:     "node report",
+			print:    func(w *bytes.Buffer) error { return NewJSONPrinter().PrintNodes(w, &types.NodeAnalysis{}) },
+			wantKind: types.NodeReportKind,
+		},
+		{
+			name: "unused image report",
+			print: func(w *bytes.Buffer) error {
+				return NewJSONPrinter().PrintUnusedImages(w, &types.UnusedImageAnalysis{})
+			},
+			wantKind: types.UnusedImageReportKind,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, tt.print(&buf))
+
+			var header types.ReportHeader
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &header))
+			assert.Equal(t, types.ReportAPIVersion, header.APIVersion)
+			assert.Equal(t, tt.wantKind, header.Kind)
+		})
+	}
+}
+
+func TestJSONPrinter_Print_RoundTrip(t *testing.T) {
+	img := types.NewImage("nginx:1.21", 100000000)
+	img.SetNodes([]string{"node1", "node2"})
+	analysis := &types.ImageAnalysis{
+		Images:      []types.Image{*img},
+		TotalSize:   100000000,
+		UniqueSize:  100000000,
+		ClusterSize: 200000000,
+		Namespaces:  []types.NamespaceUsage{{Namespace: "default", ImageCount: 1, UniqueBytes: 100000000}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewJSONPrinter().Print(&buf, analysis))
+
+	var report types.ImageReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, analysis, report.Analysis())
+}
+
+func TestJSONPrinter_PrintDiff(t *testing.T) {
+	oldAnalysis := &types.ImageAnalysis{Images: []types.Image{*types.NewImage("redis:6.2", 50)}, TotalSize: 50, UniqueSize: 50, ClusterSize: 50}
+	newAnalysis := &types.ImageAnalysis{Images: []types.Image{*types.NewImage("redis:7.0", 70)}, TotalSize: 70, UniqueSize: 70, ClusterSize: 70}
+
+	var buf bytes.Buffer
+	err := NewJSONPrinter().PrintDiff(&buf, types.DiffAnalyses(oldAnalysis, newAnalysis))
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	err = json.Unmarshal(buf.Bytes(), &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, types.ReportAPIVersion, result["apiVersion"])
+	assert.Equal(t, types.ImageDiffReportKind, result["kind"])
+
+	summary, ok := result["summary"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(50), summary["oldTotalSize"])
+	assert.Equal(t, float64(70), summary["newTotalSize"])
+
+	added, ok := result["added"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, added, 1)
+	assert.Equal(t, "redis:7.0", added[0].(map[string]interface{})["name"])
+
+	repositories, ok := result["repositories"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, repositories, 1)
+	assert.Equal(t, "docker.io/library/redis", repositories[0].(map[string]interface{})["repository"])
+
+	assert.Equal(t, []interface{}{}, result["changed"])
+	assert.Equal(t, []interface{}{}, result["namespaces"])
 }
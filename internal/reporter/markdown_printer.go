@@ -0,0 +1,233 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
+)
+
+// MarkdownPrinter formats output as GitHub-flavored Markdown, for posting
+// results into pull requests, issues and wikis
+type MarkdownPrinter struct {
+	showHistogram bool
+	topImages     int
+	sortBy        string
+}
+
+// NewMarkdownPrinter creates a new Markdown printer
+func NewMarkdownPrinter(showHistogram bool, topImages int) *MarkdownPrinter {
+	return &MarkdownPrinter{
+		showHistogram: showHistogram,
+		topImages:     topImages,
+		sortBy:        types.SortBySize,
+	}
+}
+
+// SetSortBy sets the sort order of the top images table ("size" or "cluster-size")
+func (mp *MarkdownPrinter) SetSortBy(sortBy string) {
+	mp.sortBy = sortBy
+}
+
+// Print writes the analysis as Markdown to the provided writer
+func (mp *MarkdownPrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error {
+	fmt.Fprintln(w, "## Image Analysis Summary")
+	fmt.Fprintln(w)
+	writeMarkdownTable(w, []string{"Metric", "Value"}, [][]string{
+		{"Total Images", strconv.Itoa(len(analysis.Images))},
+		{"Unique Images", strconv.Itoa(len(analysis.GetUniqueImages()))},
+		{"Total Size", util.FormatBytes(analysis.TotalSize)},
+		{"Cluster Size (all nodes)", util.FormatBytes(analysis.ClusterSize)},
+	})
+
+	// Image Size Distribution Histogram, in a code block so the bars line up
+	if mp.showHistogram && len(analysis.Images) > 0 {
+		config := types.DefaultHistogramConfig()
+		config.ShowColors = false
+
+		histogramData := analysis.GenerateImageSizeHistogram(config)
+		fmt.Fprintln(w, "## Image Size Distribution")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "```text")
+		fmt.Fprint(w, histogramData.RenderASCII(config, analysis))
+		fmt.Fprintln(w, "```")
+		fmt.Fprintln(w)
+	}
+
+	// Top images by size or by bytes used across all nodes
+	if len(analysis.Images) > 0 {
+		var topImages []types.Image
+		title := "Size"
+		if mp.sortBy == types.SortByClusterSize {
+			topImages = analysis.GetTopImagesByClusterSize(mp.topImages)
+			title = "Cluster Size"
+		} else {
+			topImages = analysis.GetTopImagesBySize(mp.topImages)
+		}
+
+		rows := make([][]string, 0, len(topImages))
+		for _, img := range topImages {
+			size := util.FormatBytes(img.Size)
+			clusterSize := util.FormatBytes(img.ClusterSize)
+			if img.Inaccessible {
+				size = "INACCESSIBLE"
+				clusterSize = "-"
+			}
+			rows = append(rows, []string{"`" + img.Name + "`", size, strconv.Itoa(img.NodeCount), clusterSize})
+		}
+		fmt.Fprintf(w, "## Top %d Images by %s\n\n", mp.topImages, title)
+		writeMarkdownTable(w, []string{"Image", "Size", "Nodes", "Cluster Size"}, rows)
+	}
+
+	// Per-namespace attribution (only when grouping by namespace)
+	if len(analysis.Namespaces) > 0 {
+		rows := make([][]string, 0, len(analysis.Namespaces))
+		for _, ns := range analysis.Namespaces {
+			rows = append(rows, []string{
+				ns.Namespace,
+				strconv.Itoa(ns.ImageCount),
+				util.FormatBytes(ns.UniqueBytes),
+				util.FormatBytes(ns.SharedBytes),
+				util.FormatBytes(ns.TotalBytes()),
+			})
+		}
+		fmt.Fprintln(w, "## Image Size by Namespace")
+		fmt.Fprintln(w)
+		writeMarkdownTable(w, []string{"Namespace", "Images", "Unique Size", "Shared Size", "Total Size"}, rows)
+	}
+
+	// Per-workload attribution (only when grouping by workload)
+	if len(analysis.Workloads) > 0 {
+		rows := make([][]string, 0, len(analysis.Workloads))
+		for _, wl := range analysis.Workloads {
+			rows = append(rows, []string{
+				wl.String(),
+				wl.Namespace,
+				strconv.Itoa(wl.Replicas),
+				strconv.Itoa(wl.NodeCount),
+				strconv.Itoa(len(wl.Images)),
+				util.FormatBytes(wl.TotalBytes),
+			})
+		}
+		fmt.Fprintln(w, "## Image Size by Workload")
+		fmt.Fprintln(w)
+		writeMarkdownTable(w, []string{"Workload", "Namespace", "Replicas", "Nodes", "Images", "Total Size"}, rows)
+	}
+
+	// Per-registry and per-repository attribution (only when grouping by registry or repository)
+	writeMarkdownRegistryUsage(w, "Registry", analysis.Registries)
+	writeMarkdownRegistryUsage(w, "Repository", analysis.Repositories)
+
+	// Per-container-type attribution (only when grouping by container type)
+	if len(analysis.ContainerTypes) > 0 {
+		rows := make([][]string, 0, len(analysis.ContainerTypes))
+		for _, ct := range analysis.ContainerTypes {
+			rows = append(rows, []string{ct.Type, strconv.Itoa(ct.ImageCount), util.FormatBytes(ct.TotalBytes), util.FormatBytes(ct.ClusterBytes)})
+		}
+		fmt.Fprintln(w, "## Image Size by Container Type")
+		fmt.Fprintln(w)
+		writeMarkdownTable(w, []string{"Container Type", "Images", "Size", "Cluster Size"}, rows)
+	}
+
+	// Tag hygiene findings (only when tag hygiene is checked)
+	if analysis.TagHygiene != nil {
+		writeMarkdownTagHygiene(w, analysis.TagHygiene)
+	}
+
+	// Tags resolving to more than one digest (only when tag drift is checked)
+	if len(analysis.TagDrift) > 0 {
+		fmt.Fprintln(w, "## Tag Drift")
+		fmt.Fprintln(w)
+		writeMarkdownTable(w, []string{"Image", "Digest", "Nodes", "Pods"}, tagDriftRows(analysis.TagDrift))
+	}
+
+	// Policy violations (only when a policy is checked)
+	if len(analysis.Violations) > 0 {
+		rows := make([][]string, 0, len(analysis.Violations))
+		for _, v := range analysis.Violations {
+			rows = append(rows, []string{v.Rule, v.Subject, v.Message})
+		}
+		fmt.Fprintln(w, "## Policy Violations")
+		fmt.Fprintln(w)
+		writeMarkdownTable(w, []string{"Rule", "Subject", "Message"}, rows)
+	}
+
+	return nil
+}
+
+// writeMarkdownTable writes a table with a header row, followed by a blank line
+func writeMarkdownTable(w io.Writer, header []string, rows [][]string) {
+	writeMarkdownRow(w, header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(w, separator)
+	for _, row := range rows {
+		writeMarkdownRow(w, row)
+	}
+	fmt.Fprintln(w)
+}
+
+// writeMarkdownRow writes a single table row, escaping characters that would
+// break the table layout
+func writeMarkdownRow(w io.Writer, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		escaped[i] = strings.ReplaceAll(cell, "\n", " ")
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+}
+
+// writeMarkdownRegistryUsage writes the image sizes attributed to registries or repositories
+func writeMarkdownRegistryUsage(w io.Writer, kind string, usage []types.RegistryUsage) {
+	if len(usage) == 0 {
+		return
+	}
+	rows := make([][]string, 0, len(usage))
+	for _, u := range usage {
+		rows = append(rows, []string{
+			u.Name,
+			strconv.Itoa(u.ImageCount),
+			util.FormatBytes(u.TotalBytes),
+			util.FormatBytes(u.ClusterBytes),
+			fmt.Sprintf("%.1f%%", u.Share*100),
+		})
+	}
+	fmt.Fprintf(w, "## Image Size by %s\n\n", kind)
+	writeMarkdownTable(w, []string{kind, "Images", "Size", "Cluster Size", "Share"}, rows)
+}
+
+// writeMarkdownTagHygiene writes the image references with tag issues and the
+// repositories running several versions
+func writeMarkdownTagHygiene(w io.Writer, hygiene *types.TagHygiene) {
+	fmt.Fprintln(w, "## Tag Hygiene")
+	fmt.Fprintln(w)
+	if !hygiene.HasIssues() {
+		fmt.Fprintln(w, "No tag hygiene issues found.")
+		fmt.Fprintln(w)
+		return
+	}
+
+	if len(hygiene.Images) > 0 {
+		rows := make([][]string, 0, len(hygiene.Images))
+		for _, f := range hygiene.Images {
+			rows = append(rows, []string{f.Image, strings.Join(f.Issues, ", "), strings.Join(f.Namespaces, ", ")})
+		}
+		writeMarkdownTable(w, []string{"Image", "Issues", "Namespaces"}, rows)
+	}
+
+	if len(hygiene.Sprawl) > 0 {
+		rows := make([][]string, 0, len(hygiene.Sprawl))
+		for _, s := range hygiene.Sprawl {
+			rows = append(rows, []string{s.Repository, strings.Join(s.Versions, ", "), strings.Join(s.Namespaces, ", ")})
+		}
+		fmt.Fprintln(w, "### Version Sprawl")
+		fmt.Fprintln(w)
+		writeMarkdownTable(w, []string{"Repository", "Versions", "Namespaces"}, rows)
+	}
+}
@@ -0,0 +1,76 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+func TestMarkdownPrinter_Print(t *testing.T) {
+	nginx := types.NewImage("nginx:1.21", 134217728)
+	nginx.SetNodes([]string{"node1", "node2"})
+	analysis := &types.ImageAnalysis{
+		Images:      []types.Image{*nginx, *types.NewInaccessibleImage("private/image:latest")},
+		TotalSize:   134217728,
+		UniqueSize:  134217728,
+		ClusterSize: 268435456,
+		Namespaces:  []types.NamespaceUsage{{Namespace: "default", ImageCount: 1, UniqueBytes: 134217728}},
+		Violations:  []types.PolicyViolation{{Rule: types.RuleLatestTag, Subject: "private/image:latest", Message: "tag a|b"}},
+	}
+
+	tests := []struct {
+		name          string
+		showHistogram bool
+		sortBy        string
+		contains      []string
+		notContains   []string
+	}{
+		{
+			name:          "all sections",
+			showHistogram: true,
+			contains: []string{
+				"## Image Analysis Summary\n\n| Metric | Value |\n| --- | --- |\n| Total Images | 2 |\n",
+				"| Total Size | 128.0 MB |\n| Cluster Size (all nodes) | 256.0 MB |\n",
+				"## Image Size Distribution\n\n```text\n",
+				"## Top 10 Images by Size\n\n| Image | Size | Nodes | Cluster Size |\n",
+				"| `nginx:1.21` | 128.0 MB | 2 | 256.0 MB |\n",
+				"| `private/image:latest` | INACCESSIBLE | 0 | - |\n",
+				"## Image Size by Namespace\n",
+				"| default | 1 | 128.0 MB | 0 B | 128.0 MB |\n",
+				"| latest-tag | private/image:latest | tag a\\|b |\n",
+			},
+			notContains: []string{"\x1b[", "┌"},
+		},
+		{
+			name:        "without histogram sorted by cluster size",
+			sortBy:      types.SortByClusterSize,
+			contains:    []string{"## Top 10 Images by Cluster Size\n"},
+			notContains: []string{"```"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp := NewMarkdownPrinter(tt.showHistogram, 10)
+			if tt.sortBy != "" {
+				mp.SetSortBy(tt.sortBy)
+			}
+			var buf bytes.Buffer
+			require.NoError(t, mp.Print(&buf, analysis))
+			output := buf.String()
+
+			for _, want := range tt.contains {
+				assert.Contains(t, output, want)
+			}
+			for _, unwanted := range tt.notContains {
+				assert.NotContains(t, output, unwanted)
+			}
+			assert.Equal(t, strings.Count(output, "```")%2, 0, "code blocks should be closed")
+		})
+	}
+}
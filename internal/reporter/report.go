@@ -1,11 +1,14 @@
 package reporter
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
 )
 
 // Reporter handles output generation
@@ -14,6 +17,8 @@ type Reporter struct {
 	showHistogram bool
 	noColor       bool
 	topImages     int
+	sortBy        string
+	columns       []string
 }
 
 // NewReporter creates a new reporter
@@ -23,6 +28,7 @@ func NewReporter(outputFormat string) *Reporter {
 		showHistogram: true, // Make histogram default
 		noColor:       false,
 		topImages:     25, // Default to 25 top images
+		sortBy:        types.SortBySize,
 	}
 }
 
@@ -41,20 +47,145 @@ func (r *Reporter) SetTopImages(count int) {
 	r.topImages = count
 }
 
+// SetSortBy sets the sort order of the top images report
+func (r *Reporter) SetSortBy(sortBy string) {
+	r.sortBy = sortBy
+}
+
+// SetColumns sets the columns of CSV and TSV output
+func (r *Reporter) SetColumns(columns []string) {
+	r.columns = columns
+}
+
 // GenerateReportTo generates a report to the specified writer
 func (r *Reporter) GenerateReportTo(w io.Writer, analysis *types.ImageAnalysis) error {
 	var printer types.Printer
 	switch r.outputFormat {
 	case "table":
-		printer = NewTablePrinter(r.showHistogram, r.noColor, r.topImages)
+		tp := NewTablePrinter(r.showHistogram, r.noColor, r.topImages)
+		tp.SetSortBy(r.sortBy)
+		printer = tp
 	case "json":
 		printer = NewJSONPrinter()
+	case "yaml":
+		printer = NewYAMLPrinter()
+	case "csv":
+		cp := NewCSVPrinter(r.columns)
+		cp.SetSortBy(r.sortBy)
+		printer = cp
+	case "tsv":
+		cp := NewTSVPrinter(r.columns)
+		cp.SetSortBy(r.sortBy)
+		printer = cp
+	case "html":
+		hp := NewHTMLPrinter()
+		hp.SetSortBy(r.sortBy)
+		printer = hp
+	case "markdown":
+		mp := NewMarkdownPrinter(r.showHistogram, r.topImages)
+		mp.SetSortBy(r.sortBy)
+		printer = mp
 	default:
-		return fmt.Errorf("unsupported output format: %s", r.outputFormat)
+		tp, err := r.templatePrinter()
+		if err != nil {
+			return err
+		}
+		printer = tp
 	}
 	return printer.Print(w, analysis)
 }
 
+// GenerateWatchUpdateTo writes one update of a watch: the analysis and, for every
+// update after the first, its changes since the previous update. Table output
+// re-renders the full report under a line describing the changes. JSON output
+// writes one compact report per line: an ImageReport for the first update and an
+// ImageDiffReport for each later one.
+func (r *Reporter) GenerateWatchUpdateTo(w io.Writer, analysis *types.ImageAnalysis, diff *types.ImageDiff, now time.Time) error {
+	switch r.outputFormat {
+	case "table":
+		if diff == nil {
+			fmt.Fprintf(w, "Initial analysis at %s\n\n", now.Format(time.TimeOnly))
+		} else {
+			fmt.Fprintf(w, "Updated at %s: %d images added, %d removed, %d changed, total size %s\n\n",
+				now.Format(time.TimeOnly), len(diff.Added), len(diff.Removed), len(diff.Changed),
+				util.FormatBytesDelta(diff.Summary.TotalDelta()))
+		}
+		return r.GenerateReportTo(w, analysis)
+	case "json":
+		var report interface{} = types.NewImageReport(analysis)
+		if diff != nil {
+			report = types.NewImageDiffReport(diff)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format for watch: %s", r.outputFormat)
+	}
+}
+
+// GenerateNodeReportTo generates a per-node report to the specified writer
+func (r *Reporter) GenerateNodeReportTo(w io.Writer, analysis *types.NodeAnalysis) error {
+	printer, err := r.nodePrinter()
+	if err != nil {
+		return err
+	}
+	return printer.PrintNodes(w, analysis)
+}
+
+// GenerateUnusedImageReportTo generates an unused image report to the specified writer
+func (r *Reporter) GenerateUnusedImageReportTo(w io.Writer, analysis *types.UnusedImageAnalysis) error {
+	printer, err := r.nodePrinter()
+	if err != nil {
+		return err
+	}
+	return printer.PrintUnusedImages(w, analysis)
+}
+
+// GenerateDiffReportTo generates an image diff report to the specified writer
+func (r *Reporter) GenerateDiffReportTo(w io.Writer, diff *types.ImageDiff) error {
+	var printer types.DiffPrinter
+	switch r.outputFormat {
+	case "table":
+		printer = NewTablePrinter(r.showHistogram, r.noColor, r.topImages)
+	case "json":
+		printer = NewJSONPrinter()
+	case "yaml":
+		printer = NewYAMLPrinter()
+	default:
+		tp, err := r.templatePrinter()
+		if err != nil {
+			return err
+		}
+		printer = tp
+	}
+	return printer.PrintDiff(w, diff)
+}
+
+// nodePrinter returns the node report printer for the configured output format
+func (r *Reporter) nodePrinter() (types.NodePrinter, error) {
+	switch r.outputFormat {
+	case "table":
+		return NewTablePrinter(r.showHistogram, r.noColor, r.topImages), nil
+	case "json":
+		return NewJSONPrinter(), nil
+	case "yaml":
+		return NewYAMLPrinter(), nil
+	default:
+		return r.templatePrinter()
+	}
+}
+
+// templatePrinter returns the template printer for a go-template, go-template-file
+// or jsonpath output format
+func (r *Reporter) templatePrinter() (*TemplatePrinter, error) {
+	if !IsTemplateFormat(r.outputFormat) {
+		return nil, fmt.Errorf("unsupported output format: %s", r.outputFormat)
+	}
+	return NewTemplatePrinter(r.outputFormat)
+}
+
 // GenerateReport generates a report to os.Stdout
 func (r *Reporter) GenerateReport(analysis *types.ImageAnalysis) error {
 	return r.GenerateReportTo(os.Stdout, analysis)
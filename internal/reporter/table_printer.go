@@ -3,7 +3,9 @@ package reporter
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 
@@ -16,6 +18,7 @@ type TablePrinter struct {
 	showHistogram bool
 	noColor       bool
 	topImages     int
+	sortBy        string
 }
 
 // NewTablePrinter creates a new table printer
@@ -24,30 +27,19 @@ func NewTablePrinter(showHistogram, noColor bool, topImages int) *TablePrinter {
 		showHistogram: showHistogram,
 		noColor:       noColor,
 		topImages:     topImages,
+		sortBy:        types.SortBySize,
 	}
 }
 
+// SetSortBy sets the sort order of the top images table ("size" or "cluster-size")
+func (tp *TablePrinter) SetSortBy(sortBy string) {
+	tp.sortBy = sortBy
+}
+
 // Print writes the analysis as formatted tables to the provided writer
 func (tp *TablePrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error {
 	// Performance Summary
-	if analysis.Performance != nil {
-		fmt.Fprintln(w, "Performance Summary")
-		fmt.Fprintln(w, "==================")
-
-		performanceTable := tablewriter.NewWriter(w)
-		performanceTable.Header("Metric", "Value")
-		if analysis.Performance.PodQueryTime > 0 {
-			_ = performanceTable.Append("Pod Query Time", analysis.Performance.PodQueryTime.String())
-		}
-		if analysis.Performance.NodeQueryTime > 0 {
-			_ = performanceTable.Append("Node Query Time", analysis.Performance.NodeQueryTime.String())
-		}
-		_ = performanceTable.Append("Image Analysis Time", analysis.Performance.ImageAnalysisTime.String())
-		_ = performanceTable.Append("Total Time", analysis.Performance.TotalTime.String())
-		_ = performanceTable.Append("Images Processed", strconv.Itoa(analysis.Performance.ImagesProcessed))
-		_ = performanceTable.Render()
-		fmt.Fprintln(w)
-	}
+	printPerformanceSummary(w, analysis.Performance)
 
 	// Image Analysis Summary
 	fmt.Fprintln(w, "Image Analysis Summary")
@@ -58,6 +50,7 @@ func (tp *TablePrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error
 	_ = summaryTable.Append("Total Images", strconv.Itoa(len(analysis.Images)))
 	_ = summaryTable.Append("Unique Images", strconv.Itoa(len(analysis.GetUniqueImages())))
 	_ = summaryTable.Append("Total Size", util.FormatBytes(analysis.TotalSize))
+	_ = summaryTable.Append("Cluster Size (all nodes)", util.FormatBytes(analysis.ClusterSize))
 	_ = summaryTable.Render()
 	fmt.Fprintln(w)
 
@@ -76,26 +69,481 @@ func (tp *TablePrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error
 		fmt.Fprint(w, histogramData.RenderASCII(config, analysis))
 	}
 
-	// Top images by size
+	// Top images by size or by bytes used across all nodes
 	if len(analysis.Images) > 0 {
 		fmt.Fprintln(w)
-		fmt.Fprintf(w, "Top %d Images by Size\n", tp.topImages)
-		fmt.Fprintln(w, "=====================")
+		var topImages []types.Image
+		title := "Size"
+		if tp.sortBy == types.SortByClusterSize {
+			topImages = analysis.GetTopImagesByClusterSize(tp.topImages)
+			title = "Cluster Size"
+		} else {
+			topImages = analysis.GetTopImagesBySize(tp.topImages)
+		}
 
+		fmt.Fprintf(w, "Top %d Images by %s\n", tp.topImages, title)
+		fmt.Fprintln(w, "=====================")
 		imageTable := tablewriter.NewWriter(w)
-		imageTable.Header("Image", "Size")
-
-		topImages := analysis.GetTopImagesBySize(tp.topImages)
+		imageTable.Header("Image", "Size", "Nodes", "Cluster Size")
 		for _, img := range topImages {
 			size := util.FormatBytes(img.Size)
+			clusterSize := util.FormatBytes(img.ClusterSize)
 			if img.Inaccessible {
 				size = "INACCESSIBLE"
+				clusterSize = "-"
 			}
-			_ = imageTable.Append(img.Name, size)
+			_ = imageTable.Append(img.Name, size, strconv.Itoa(img.NodeCount), clusterSize)
 		}
 		_ = imageTable.Render()
 		fmt.Fprintln(w)
 	}
 
+	// Per-namespace attribution (only when grouping by namespace)
+	if len(analysis.Namespaces) > 0 {
+		fmt.Fprintln(w, "Image Size by Namespace")
+		fmt.Fprintln(w, "=======================")
+		namespaceTable := tablewriter.NewWriter(w)
+		namespaceTable.Header("Namespace", "Images", "Unique Size", "Shared Size", "Total Size")
+		for _, ns := range analysis.Namespaces {
+			_ = namespaceTable.Append(
+				ns.Namespace,
+				strconv.Itoa(ns.ImageCount),
+				util.FormatBytes(ns.UniqueBytes),
+				util.FormatBytes(ns.SharedBytes),
+				util.FormatBytes(ns.TotalBytes()),
+			)
+		}
+		_ = namespaceTable.Render()
+		fmt.Fprintln(w)
+	}
+
+	// Per-workload attribution (only when grouping by workload)
+	if len(analysis.Workloads) > 0 {
+		fmt.Fprintln(w, "Image Size by Workload")
+		fmt.Fprintln(w, "======================")
+		workloadTable := tablewriter.NewWriter(w)
+		workloadTable.Header("Workload", "Namespace", "Replicas", "Nodes", "Images", "Total Size")
+		for _, wl := range analysis.Workloads {
+			_ = workloadTable.Append(
+				wl.String(),
+				wl.Namespace,
+				strconv.Itoa(wl.Replicas),
+				strconv.Itoa(wl.NodeCount),
+				strconv.Itoa(len(wl.Images)),
+				util.FormatBytes(wl.TotalBytes),
+			)
+		}
+		_ = workloadTable.Render()
+		fmt.Fprintln(w)
+	}
+
+	// Per-registry and per-repository attribution (only when grouping by registry or repository)
+	tp.printRegistryUsage(w, "Registry", analysis.Registries)
+	tp.printRegistryUsage(w, "Repository", analysis.Repositories)
+
+	// Per-container-type attribution (only when grouping by container type)
+	if len(analysis.ContainerTypes) > 0 {
+		fmt.Fprintln(w, "Image Size by Container Type")
+		fmt.Fprintln(w, "============================")
+		typeTable := tablewriter.NewWriter(w)
+		typeTable.Header("Container Type", "Images", "Size", "Cluster Size")
+		for _, ct := range analysis.ContainerTypes {
+			_ = typeTable.Append(ct.Type, strconv.Itoa(ct.ImageCount), util.FormatBytes(ct.TotalBytes), util.FormatBytes(ct.ClusterBytes))
+		}
+		_ = typeTable.Render()
+		fmt.Fprintln(w)
+	}
+
+	// Tag hygiene findings (only when tag hygiene is checked)
+	if analysis.TagHygiene != nil {
+		tp.printTagHygiene(w, analysis.TagHygiene)
+	}
+
+	// Tags resolving to more than one digest (only when tag drift is checked)
+	if len(analysis.TagDrift) > 0 {
+		fmt.Fprintln(w, "Tag Drift")
+		fmt.Fprintln(w, "=========")
+		driftTable := tablewriter.NewWriter(w)
+		driftTable.Header("Image", "Digest", "Nodes", "Pods")
+		for _, row := range tagDriftRows(analysis.TagDrift) {
+			_ = driftTable.Append(row)
+		}
+		_ = driftTable.Render()
+		fmt.Fprintln(w)
+	}
+
+	// Policy violations (only when a policy is checked)
+	if len(analysis.Violations) > 0 {
+		fmt.Fprintln(w, "Policy Violations")
+		fmt.Fprintln(w, "=================")
+		violationTable := tablewriter.NewWriter(w)
+		violationTable.Header("Rule", "Subject", "Message")
+		for _, v := range analysis.Violations {
+			_ = violationTable.Append(v.Rule, v.Subject, v.Message)
+		}
+		_ = violationTable.Render()
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+// PrintNodes writes the per-node image footprint as formatted tables to the provided writer
+func (tp *TablePrinter) PrintNodes(w io.Writer, analysis *types.NodeAnalysis) error {
+	// Performance Summary
+	printPerformanceSummary(w, analysis.Performance)
+
+	// Node Summary
+	fmt.Fprintln(w, "Node Image Summary")
+	fmt.Fprintln(w, "==================")
+	summaryTable := tablewriter.NewWriter(w)
+	summaryTable.Header("Metric", "Value")
+	_ = summaryTable.Append("Total Nodes", strconv.Itoa(len(analysis.Nodes)))
+	_ = summaryTable.Append("Total Image Size", util.FormatBytes(analysis.TotalBytes()))
+	_ = summaryTable.Append("Unreferenced Image Size", util.FormatBytes(analysis.UnreferencedBytes()))
+	_ = summaryTable.Render()
+	fmt.Fprintln(w)
+
+	if len(analysis.Nodes) == 0 {
+		return nil
+	}
+
+	// Per-node footprint
+	fmt.Fprintln(w, "Image Disk Footprint by Node")
+	fmt.Fprintln(w, "============================")
+	nodeTable := tablewriter.NewWriter(w)
+	nodeTable.Header("Node", "Images", "Image Size", "Unreferenced", "Ephemeral Capacity", "Ephemeral Allocatable", "% Allocatable")
+	for _, node := range analysis.Nodes {
+		percent := "-"
+		if node.EphemeralStorageAllocatable > 0 {
+			percent = fmt.Sprintf("%.1f%%", node.AllocatablePercent())
+		}
+		_ = nodeTable.Append(
+			node.Name,
+			strconv.Itoa(node.ImageCount),
+			util.FormatBytes(node.TotalBytes),
+			util.FormatBytes(node.UnreferencedBytes),
+			util.FormatBytes(node.EphemeralStorageCapacity),
+			util.FormatBytes(node.EphemeralStorageAllocatable),
+			percent,
+		)
+	}
+	_ = nodeTable.Render()
+	fmt.Fprintln(w)
+
 	return nil
 }
+
+// PrintUnusedImages writes the unused images cached on nodes as formatted tables to the provided writer
+func (tp *TablePrinter) PrintUnusedImages(w io.Writer, analysis *types.UnusedImageAnalysis) error {
+	// Performance Summary
+	printPerformanceSummary(w, analysis.Performance)
+
+	scope := "pods scheduled to the same node"
+	if analysis.ClusterWide {
+		scope = "any pod in the cluster"
+	}
+
+	// Unused Image Summary
+	fmt.Fprintln(w, "Unused Image Summary")
+	fmt.Fprintln(w, "====================")
+	summaryTable := tablewriter.NewWriter(w)
+	summaryTable.Header("Metric", "Value")
+	_ = summaryTable.Append("Referenced By", scope)
+	_ = summaryTable.Append("Nodes With Unused Images", strconv.Itoa(len(analysis.Nodes)))
+	_ = summaryTable.Append("Unused Images", strconv.Itoa(analysis.ImageCount()))
+	_ = summaryTable.Append("Reclaimable Size", util.FormatBytes(analysis.ReclaimableBytes()))
+	_ = summaryTable.Render()
+	fmt.Fprintln(w)
+
+	if len(analysis.Nodes) == 0 {
+		return nil
+	}
+
+	// Reclaimable bytes per node
+	fmt.Fprintln(w, "Reclaimable Space by Node")
+	fmt.Fprintln(w, "=========================")
+	nodeTable := tablewriter.NewWriter(w)
+	nodeTable.Header("Node", "Unused Images", "Reclaimable Size")
+	for _, node := range analysis.Nodes {
+		_ = nodeTable.Append(node.Node, strconv.Itoa(len(node.Images)), util.FormatBytes(node.ReclaimableBytes))
+	}
+	_ = nodeTable.Render()
+	fmt.Fprintln(w)
+
+	// Largest unused images across all nodes
+	type nodeImage struct {
+		node  string
+		image types.UnusedImage
+	}
+	var largest []nodeImage
+	for _, node := range analysis.Nodes {
+		for _, image := range node.Images {
+			largest = append(largest, nodeImage{node: node.Node, image: image})
+		}
+	}
+	sort.SliceStable(largest, func(i, j int) bool {
+		return largest[i].image.Size > largest[j].image.Size
+	})
+	if len(largest) > tp.topImages {
+		largest = largest[:tp.topImages]
+	}
+
+	fmt.Fprintf(w, "Top %d Unused Images by Size\n", tp.topImages)
+	fmt.Fprintln(w, "============================")
+	imageTable := tablewriter.NewWriter(w)
+	imageTable.Header("Node", "Image", "Size")
+	for _, entry := range largest {
+		_ = imageTable.Append(entry.node, entry.image.Name, util.FormatBytes(entry.image.Size))
+	}
+	_ = imageTable.Render()
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// printPerformanceSummary writes the performance metrics table, if metrics are present
+func printPerformanceSummary(w io.Writer, perf *types.PerformanceMetrics) {
+	if perf == nil {
+		return
+	}
+
+	fmt.Fprintln(w, "Performance Summary")
+	fmt.Fprintln(w, "==================")
+
+	performanceTable := tablewriter.NewWriter(w)
+	performanceTable.Header("Metric", "Value")
+	if perf.PodQueryTime > 0 {
+		_ = performanceTable.Append("Pod Query Time", perf.PodQueryTime.String())
+	}
+	if perf.NodeQueryTime > 0 {
+		_ = performanceTable.Append("Node Query Time", perf.NodeQueryTime.String())
+	}
+	if perf.OwnerQueryTime > 0 {
+		_ = performanceTable.Append("Owner Query Time", perf.OwnerQueryTime.String())
+	}
+	// Queries run concurrently, so the wall-clock time is less than their sum
+	if perf.QueryTime > 0 {
+		_ = performanceTable.Append("Query Time (wall clock)", perf.QueryTime.String())
+		_ = performanceTable.Append("Query Time (summed)", perf.SummedQueryTime.String())
+	}
+	_ = performanceTable.Append("Image Analysis Time", perf.ImageAnalysisTime.String())
+	_ = performanceTable.Append("Total Time", perf.TotalTime.String())
+	_ = performanceTable.Append("Images Processed", strconv.Itoa(perf.ImagesProcessed))
+	_ = performanceTable.Render()
+	fmt.Fprintln(w)
+}
+
+// PrintDiff writes the differences between two analyses as formatted tables to the provided writer
+func (tp *TablePrinter) PrintDiff(w io.Writer, diff *types.ImageDiff) error {
+	// Diff Summary
+	fmt.Fprintln(w, "Image Diff Summary")
+	fmt.Fprintln(w, "==================")
+	summaryTable := tablewriter.NewWriter(w)
+	summaryTable.Header("Metric", "Old", "New", "Change")
+	_ = summaryTable.Append("Added Images", "-", "-", strconv.Itoa(len(diff.Added)))
+	_ = summaryTable.Append("Removed Images", "-", "-", strconv.Itoa(len(diff.Removed)))
+	_ = summaryTable.Append("Changed Images", "-", "-", strconv.Itoa(len(diff.Changed)))
+	_ = summaryTable.Append(
+		"Unique Size",
+		util.FormatBytes(diff.Summary.OldUniqueSize),
+		util.FormatBytes(diff.Summary.NewUniqueSize),
+		util.FormatBytesDelta(diff.Summary.UniqueDelta()),
+	)
+	_ = summaryTable.Append(
+		"Total Size (all nodes)",
+		util.FormatBytes(diff.Summary.OldTotalSize),
+		util.FormatBytes(diff.Summary.NewTotalSize),
+		util.FormatBytesDelta(diff.Summary.TotalDelta()),
+	)
+	_ = summaryTable.Render()
+	fmt.Fprintln(w)
+
+	tp.printDiffImages(w, "Added Images", diff.Added)
+	tp.printDiffImages(w, "Removed Images", diff.Removed)
+
+	// Images whose name is unchanged but whose size changed
+	if len(diff.Changed) > 0 {
+		fmt.Fprintln(w, "Changed Images")
+		fmt.Fprintln(w, "==============")
+		changedTable := tablewriter.NewWriter(w)
+		changedTable.Header("Image", "Old Size", "New Size", "Change")
+		for _, change := range diff.Changed {
+			_ = changedTable.Append(
+				change.Name,
+				util.FormatBytes(change.OldSize),
+				util.FormatBytes(change.NewSize),
+				util.FormatBytesDelta(change.SizeDelta()),
+			)
+		}
+		_ = changedTable.Render()
+		fmt.Fprintln(w)
+	}
+
+	// Repositories whose tags or size changed
+	if len(diff.Repositories) > 0 {
+		fmt.Fprintln(w, "Repository Size Changes")
+		fmt.Fprintln(w, "=======================")
+		repoTable := tablewriter.NewWriter(w)
+		repoTable.Header("Repository", "Old Tags", "New Tags", "Old Size", "New Size", "Change")
+		for _, repo := range diff.Repositories {
+			_ = repoTable.Append(
+				repo.Repository,
+				strings.Join(repo.OldTags, ", "),
+				strings.Join(repo.NewTags, ", "),
+				util.FormatBytes(repo.OldSize),
+				util.FormatBytes(repo.NewSize),
+				util.FormatBytesDelta(repo.SizeDelta()),
+			)
+		}
+		_ = repoTable.Render()
+		fmt.Fprintln(w)
+	}
+
+	printSizeChanges(w, "Namespace", diff.Namespaces)
+	printSizeChanges(w, "Registry", diff.Registries)
+
+	return nil
+}
+
+// printDiffImages writes the largest added or removed images, if any
+func (tp *TablePrinter) printDiffImages(w io.Writer, title string, images []types.Image) {
+	if len(images) == 0 {
+		return
+	}
+
+	shown := images
+	if len(shown) > tp.topImages {
+		shown = shown[:tp.topImages]
+		title = fmt.Sprintf("%s (largest %d of %d)", title, tp.topImages, len(images))
+	}
+
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("=", len(title)))
+	imageTable := tablewriter.NewWriter(w)
+	imageTable.Header("Image", "Size", "Nodes", "Cluster Size")
+	for _, img := range shown {
+		_ = imageTable.Append(img.Name, util.FormatBytes(img.Size), strconv.Itoa(img.NodeCount), util.FormatBytes(img.ClusterSize))
+	}
+	_ = imageTable.Render()
+	fmt.Fprintln(w)
+}
+
+// printSizeChanges writes the per-group size changes, if any
+func printSizeChanges(w io.Writer, group string, changes []types.SizeChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	title := fmt.Sprintf("Size Change by %s", group)
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("=", len(title)))
+	changeTable := tablewriter.NewWriter(w)
+	changeTable.Header(group, "Unique Size", "Unique Change", "Total Size", "Total Change")
+	for _, change := range changes {
+		_ = changeTable.Append(
+			change.Name,
+			util.FormatBytes(change.NewUniqueSize),
+			util.FormatBytesDelta(change.UniqueDelta()),
+			util.FormatBytes(change.NewTotalSize),
+			util.FormatBytesDelta(change.TotalDelta()),
+		)
+	}
+	_ = changeTable.Render()
+	fmt.Fprintln(w)
+}
+
+// printRegistryUsage prints the image sizes attributed to registries or repositories
+func (tp *TablePrinter) printRegistryUsage(w io.Writer, kind string, usage []types.RegistryUsage) {
+	if len(usage) == 0 {
+		return
+	}
+	title := "Image Size by " + kind
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("=", len(title)))
+	usageTable := tablewriter.NewWriter(w)
+	usageTable.Header(kind, "Images", "Size", "Cluster Size", "Share")
+	for _, u := range usage {
+		_ = usageTable.Append(
+			u.Name,
+			strconv.Itoa(u.ImageCount),
+			util.FormatBytes(u.TotalBytes),
+			util.FormatBytes(u.ClusterBytes),
+			fmt.Sprintf("%.1f%%", u.Share*100),
+		)
+	}
+	_ = usageTable.Render()
+	fmt.Fprintln(w)
+}
+
+// printTagHygiene prints the image references with tag issues and the repositories
+// running several versions
+func (tp *TablePrinter) printTagHygiene(w io.Writer, hygiene *types.TagHygiene) {
+	fmt.Fprintln(w, "Tag Hygiene")
+	fmt.Fprintln(w, "===========")
+	if !hygiene.HasIssues() {
+		fmt.Fprintln(w, "No tag hygiene issues found")
+		fmt.Fprintln(w)
+		return
+	}
+
+	if len(hygiene.Images) > 0 {
+		findingTable := tablewriter.NewWriter(w)
+		findingTable.Header("Image", "Issues", "Namespaces")
+		for _, f := range hygiene.Images {
+			_ = findingTable.Append(f.Image, strings.Join(f.Issues, ", "), strings.Join(f.Namespaces, ", "))
+		}
+		_ = findingTable.Render()
+		fmt.Fprintln(w)
+	}
+
+	if len(hygiene.Sprawl) > 0 {
+		fmt.Fprintln(w, "Version Sprawl")
+		fmt.Fprintln(w, "==============")
+		sprawlTable := tablewriter.NewWriter(w)
+		sprawlTable.Header("Repository", "Versions", "Namespaces")
+		for _, s := range hygiene.Sprawl {
+			_ = sprawlTable.Append(s.Repository, strings.Join(s.Versions, ", "), strings.Join(s.Namespaces, ", "))
+		}
+		_ = sprawlTable.Render()
+		fmt.Fprintln(w)
+	}
+}
+
+// maxDriftNames is the number of nodes or pods listed per digest in tag drift tables
+const maxDriftNames = 5
+
+// tagDriftRows returns a row per digest of each drifting tag, naming the tag on its first row only
+func tagDriftRows(drift []types.TagDrift) [][]string {
+	var rows [][]string
+	for _, td := range drift {
+		for i, du := range td.Digests {
+			image := ""
+			if i == 0 {
+				image = td.Image
+			}
+			rows = append(rows, []string{image, shortDigest(du.Digest), joinNames(du.Nodes, maxDriftNames), joinNames(du.Pods, maxDriftNames)})
+		}
+	}
+	return rows
+}
+
+// shortDigest abbreviates a digest to its algorithm and the first 12 hex characters
+func shortDigest(digest string) string {
+	algorithm, hex, found := strings.Cut(digest, ":")
+	if !found || len(hex) <= 12 {
+		return digest
+	}
+	return algorithm + ":" + hex[:12]
+}
+
+// joinNames joins up to max names, noting how many more were left out
+func joinNames(names []string, max int) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	if len(names) <= max {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(names[:max], ", "), len(names)-max)
+}
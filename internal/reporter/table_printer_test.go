@@ -138,6 +138,155 @@ func TestTablePrinter_Print(t *testing.T) {
 			},
 			wantNotContain: []string{},
 		},
+		{
+			name: "namespace breakdown",
+			analysis: &types.ImageAnalysis{
+				Images: []types.Image{
+					{Name: "nginx:1.21", Size: 133000000, Registry: "docker.io", Tag: "1.21"},
+				},
+				TotalSize:  133000000,
+				UniqueSize: 133000000,
+				Namespaces: []types.NamespaceUsage{
+					{Namespace: "team-a", ImageCount: 1, UniqueBytes: 133000000},
+				},
+			},
+			showHistogram: false,
+			noColor:       true,
+			topImages:     25,
+			wantContains: []string{
+				"Image Size by Namespace",
+				"team-a",
+				"SHARED SIZE",
+			},
+			wantNotContain: []string{},
+		},
+		{
+			name: "workload breakdown",
+			analysis: &types.ImageAnalysis{
+				Images: []types.Image{
+					{Name: "nginx:1.21", Size: 133000000, Registry: "docker.io", Tag: "1.21"},
+				},
+				TotalSize:  133000000,
+				UniqueSize: 133000000,
+				Workloads: []types.WorkloadUsage{
+					{
+						Workload:   types.Workload{Kind: "Deployment", Namespace: "default", Name: "web"},
+						Replicas:   3,
+						NodeCount:  2,
+						Images:     []string{"nginx:1.21"},
+						TotalBytes: 133000000,
+					},
+				},
+			},
+			showHistogram: false,
+			noColor:       true,
+			topImages:     25,
+			wantContains: []string{
+				"Image Size by Workload",
+				"Deployment/web",
+				"REPLICAS",
+			},
+			wantNotContain: []string{
+				"Image Size by Namespace",
+			},
+		},
+		{
+			name: "registry and repository breakdown",
+			analysis: &types.ImageAnalysis{
+				Images: []types.Image{
+					{Name: "nginx:1.21", Size: 133000000, Registry: "docker.io", Tag: "1.21"},
+				},
+				TotalSize:  133000000,
+				UniqueSize: 133000000,
+				Registries: []types.RegistryUsage{
+					{Name: "docker.io", ImageCount: 1, TotalBytes: 133000000, ClusterBytes: 266000000, Share: 1},
+				},
+				Repositories: []types.RegistryUsage{
+					{Name: "docker.io/library/nginx", ImageCount: 1, TotalBytes: 133000000, ClusterBytes: 266000000, Share: 1},
+				},
+			},
+			showHistogram: false,
+			noColor:       true,
+			topImages:     25,
+			wantContains: []string{
+				"Image Size by Registry",
+				"Image Size by Repository",
+				"docker.io/library/nginx",
+				"100.0%",
+			},
+			wantNotContain: []string{
+				"Image Size by Namespace",
+			},
+		},
+		{
+			name: "tag hygiene without issues",
+			analysis: &types.ImageAnalysis{
+				Images: []types.Image{
+					{Name: "nginx:1.21", Size: 133000000, Registry: "docker.io", Tag: "1.21"},
+				},
+				TotalSize:  133000000,
+				UniqueSize: 133000000,
+				TagHygiene: &types.TagHygiene{},
+			},
+			showHistogram: false,
+			noColor:       true,
+			topImages:     25,
+			wantContains: []string{
+				"Tag Hygiene",
+				"No tag hygiene issues found",
+			},
+			wantNotContain: []string{
+				"Version Sprawl",
+			},
+		},
+		{
+			name: "tag drift",
+			analysis: &types.ImageAnalysis{
+				Images: []types.Image{
+					{Name: "docker.io/acme/app:stable", Size: 133000000, Registry: "docker.io", Tag: "stable"},
+				},
+				TotalSize:  133000000,
+				UniqueSize: 133000000,
+				TagDrift: []types.TagDrift{
+					{
+						Image: "docker.io/acme/app:stable",
+						Digests: []types.DigestUsage{
+							{Digest: "sha256:aaaaaaaaaaaaaaaaaaaa", Nodes: []string{"node1"}, Pods: []string{"web/app-1"}},
+							{Digest: "sha256:bbbbbbbbbbbbbbbbbbbb", Nodes: []string{"n1", "n2", "n3", "n4", "n5", "n6", "n7"}},
+						},
+					},
+				},
+			},
+			showHistogram: false,
+			noColor:       true,
+			topImages:     25,
+			wantContains: []string{
+				"Tag Drift",
+				"sha256:aaaaaaaaaaaa",
+				"web/app-1",
+				"n1, n2, n3, n4, n5 (+2 more)",
+			},
+			wantNotContain: []string{
+				"sha256:aaaaaaaaaaaaa",
+			},
+		},
+		{
+			name: "no namespace breakdown without grouping",
+			analysis: &types.ImageAnalysis{
+				Images: []types.Image{
+					{Name: "nginx:1.21", Size: 133000000, Registry: "docker.io", Tag: "1.21"},
+				},
+				TotalSize:  133000000,
+				UniqueSize: 133000000,
+			},
+			showHistogram: false,
+			noColor:       true,
+			topImages:     25,
+			wantContains:  []string{},
+			wantNotContain: []string{
+				"Image Size by Namespace",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -240,3 +389,182 @@ func TestTablePrinter_Print_TopImagesLimit(t *testing.T) {
 	// We expect to see exactly 3 images in the top images section
 	assert.Equal(t, 3, imageCount, "should display exactly 3 images")
 }
+
+func TestTablePrinter_PrintNodes(t *testing.T) {
+	analysis := &types.NodeAnalysis{
+		Nodes: []types.NodeUsage{
+			{
+				Name:                        "node1",
+				ImageCount:                  12,
+				TotalBytes:                  4000000000,
+				UnreferencedBytes:           1000000000,
+				EphemeralStorageCapacity:    100000000000,
+				EphemeralStorageAllocatable: 80000000000,
+			},
+			{Name: "node2", ImageCount: 3, TotalBytes: 500000000},
+		},
+		Performance: &types.PerformanceMetrics{
+			PodQueryTime:  100 * time.Millisecond,
+			NodeQueryTime: 50 * time.Millisecond,
+		},
+	}
+
+	var buf bytes.Buffer
+	printer := NewTablePrinter(false, true, 25)
+
+	err := printer.PrintNodes(&buf, analysis)
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "Performance Summary")
+	assert.Contains(t, output, "Node Image Summary")
+	assert.Contains(t, output, "Image Disk Footprint by Node")
+	assert.Contains(t, output, "node1")
+	assert.Contains(t, output, "5.0%")
+	assert.Contains(t, output, "node2")
+}
+
+func TestTablePrinter_PrintNodes_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	printer := NewTablePrinter(false, true, 25)
+
+	err := printer.PrintNodes(&buf, &types.NodeAnalysis{})
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "Node Image Summary")
+	assert.NotContains(t, output, "Image Disk Footprint by Node")
+}
+
+func TestTablePrinter_PrintUnusedImages(t *testing.T) {
+	analysis := &types.UnusedImageAnalysis{
+		Nodes: []types.NodeUnusedImages{
+			{
+				Node: "node1",
+				Images: []types.UnusedImage{
+					{Name: "old-app:v1", Size: 300000000},
+					{Name: "tiny:v1", Size: 1000000},
+				},
+				ReclaimableBytes: 301000000,
+			},
+			{
+				Node:             "node2",
+				Images:           []types.UnusedImage{{Name: "legacy:v3", Size: 200000000}},
+				ReclaimableBytes: 200000000,
+			},
+		},
+		ClusterWide: true,
+	}
+
+	var buf bytes.Buffer
+	printer := NewTablePrinter(false, true, 2)
+
+	err := printer.PrintUnusedImages(&buf, analysis)
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "Unused Image Summary")
+	assert.Contains(t, output, "any pod in the cluster")
+	assert.Contains(t, output, "Reclaimable Space by Node")
+	assert.Contains(t, output, "Top 2 Unused Images by Size")
+	assert.Contains(t, output, "old-app:v1")
+	assert.Contains(t, output, "legacy:v3")
+	assert.NotContains(t, output, "tiny:v1", "only the top 2 images should be listed")
+}
+
+func TestTablePrinter_Print_SortByClusterSize(t *testing.T) {
+	analysis := &types.ImageAnalysis{
+		Images: []types.Image{
+			{Name: "big-on-one-node:v1", Size: 500000000, NodeCount: 1, ClusterSize: 500000000},
+			{Name: "small-everywhere:v1", Size: 100000000, NodeCount: 30, ClusterSize: 3000000000},
+		},
+		TotalSize:   600000000,
+		UniqueSize:  600000000,
+		ClusterSize: 3500000000,
+	}
+
+	var buf bytes.Buffer
+	printer := NewTablePrinter(false, true, 1)
+	printer.SetSortBy(types.SortByClusterSize)
+
+	err := printer.Print(&buf, analysis)
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "Top 1 Images by Cluster Size")
+	assert.Contains(t, output, "small-everywhere:v1")
+	assert.NotContains(t, output, "big-on-one-node:v1")
+	assert.Contains(t, output, "572.2 MB")
+	assert.Contains(t, output, "Cluster Size (all nodes)")
+	assert.Contains(t, output, "3.3 GB")
+}
+
+func TestTablePrinter_PrintDiff(t *testing.T) {
+	diff := &types.ImageDiff{
+		Summary: types.SizeChange{OldTotalSize: 1000000000, NewTotalSize: 4000000000, OldUniqueSize: 500000000, NewUniqueSize: 800000000},
+		Added: []types.Image{
+			{Name: "gcr.io/team/app:v2", Size: 800000000, NodeCount: 3, ClusterSize: 2400000000},
+			{Name: "sidecar:v1", Size: 1000000, NodeCount: 1, ClusterSize: 1000000},
+		},
+		Removed: []types.Image{{Name: "gcr.io/team/app:v1", Size: 500000000, NodeCount: 1, ClusterSize: 500000000}},
+		Changed: []types.ImageChange{{Name: "myapp:stable", OldSize: 100000000, NewSize: 150000000}},
+		Repositories: []types.RepositoryChange{
+			{Repository: "gcr.io/team/app", OldTags: []string{"v1"}, NewTags: []string{"v2"}, OldSize: 500000000, NewSize: 800000000},
+		},
+		Namespaces: []types.SizeChange{{Name: "payments", OldTotalSize: 500000000, NewTotalSize: 2400000000, NewUniqueSize: 800000000}},
+		Registries: []types.SizeChange{{Name: "gcr.io", OldTotalSize: 500000000, NewTotalSize: 2400000000}},
+	}
+
+	var buf bytes.Buffer
+	printer := NewTablePrinter(false, true, 1)
+
+	err := printer.PrintDiff(&buf, diff)
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "Image Diff Summary")
+	assert.Contains(t, output, "+2.8 GB")
+	assert.Contains(t, output, "Added Images (largest 1 of 2)")
+	assert.Contains(t, output, "gcr.io/team/app:v2")
+	assert.NotContains(t, output, "sidecar:v1", "only the top 1 added image should be listed")
+	assert.Contains(t, output, "Removed Images")
+	assert.Contains(t, output, "Changed Images")
+	assert.Contains(t, output, "myapp:stable")
+	assert.Contains(t, output, "+47.7 MB")
+	assert.Contains(t, output, "Repository Size Changes")
+	assert.Contains(t, output, "+286.1 MB")
+	assert.Contains(t, output, "Size Change by Namespace")
+	assert.Contains(t, output, "payments")
+	assert.Contains(t, output, "Size Change by Registry")
+}
+
+func TestTablePrinter_PrintDiff_NoChanges(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewTablePrinter(false, true, 25).PrintDiff(&buf, &types.ImageDiff{})
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "Image Diff Summary")
+	assert.NotContains(t, output, "Added Images\n")
+	assert.NotContains(t, output, "Changed Images\n")
+	assert.NotContains(t, output, "Repository Size Changes")
+	assert.NotContains(t, output, "Size Change by")
+}
+
+func TestTablePrinter_Print_PolicyViolations(t *testing.T) {
+	analysis := &types.ImageAnalysis{
+		Images: []types.Image{{Name: "nginx:latest", Size: 100000000}},
+		Violations: []types.PolicyViolation{
+			{Rule: types.RuleLatestTag, Subject: "nginx:latest", Message: "image uses the :latest tag"},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := NewTablePrinter(false, true, 25).Print(&buf, analysis)
+	require.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, "Policy Violations")
+	assert.Contains(t, output, "latest-tag")
+	assert.Contains(t, output, "image uses the :latest tag")
+}
@@ -0,0 +1,127 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// Template output format prefixes, as in `kubectl get -o`
+const (
+	goTemplatePrefix     = "go-template="
+	goTemplateFilePrefix = "go-template-file="
+	jsonPathPrefix       = "jsonpath="
+)
+
+// IsTemplateFormat reports whether the output format is a go-template,
+// go-template-file or jsonpath format
+func IsTemplateFormat(format string) bool {
+	return strings.HasPrefix(format, goTemplatePrefix) ||
+		strings.HasPrefix(format, goTemplateFilePrefix) ||
+		strings.HasPrefix(format, jsonPathPrefix)
+}
+
+// TemplatePrinter formats output with a Go template or JSONPath expression evaluated
+// against the report JSONPrinter serializes, so fields use their JSON names
+// (e.g. {{.summary.totalSize}} or {.images[*].name}).
+type TemplatePrinter struct {
+	execute func(w io.Writer, data interface{}) error
+}
+
+// NewTemplatePrinter creates a printer for a "go-template=...", "go-template-file=..."
+// or "jsonpath=..." output format
+func NewTemplatePrinter(format string) (*TemplatePrinter, error) {
+	switch {
+	case strings.HasPrefix(format, goTemplatePrefix):
+		return newGoTemplatePrinter(strings.TrimPrefix(format, goTemplatePrefix))
+	case strings.HasPrefix(format, goTemplateFilePrefix):
+		path := strings.TrimPrefix(format, goTemplateFilePrefix)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template file: %w", err)
+		}
+		return newGoTemplatePrinter(string(data))
+	case strings.HasPrefix(format, jsonPathPrefix):
+		return newJSONPathPrinter(strings.TrimPrefix(format, jsonPathPrefix))
+	default:
+		return nil, fmt.Errorf("unsupported template format: %s", format)
+	}
+}
+
+// newGoTemplatePrinter parses a Go template
+func newGoTemplatePrinter(text string) (*TemplatePrinter, error) {
+	if text == "" {
+		return nil, fmt.Errorf("template format specified but no template given")
+	}
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return &TemplatePrinter{execute: tmpl.Execute}, nil
+}
+
+// newJSONPathPrinter parses a JSONPath expression. Like kubectl, the surrounding
+// braces are optional for a single expression.
+func newJSONPathPrinter(expr string) (*TemplatePrinter, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("jsonpath format specified but no expression given")
+	}
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+
+	jp := jsonpath.New("output").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return nil, fmt.Errorf("failed to parse jsonpath expression: %w", err)
+	}
+	return &TemplatePrinter{execute: jp.Execute}, nil
+}
+
+// Print evaluates the template against the image analysis report
+func (tp *TemplatePrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error {
+	return tp.executeReport(w, types.NewImageReport(analysis))
+}
+
+// PrintNodes evaluates the template against the per-node report
+func (tp *TemplatePrinter) PrintNodes(w io.Writer, analysis *types.NodeAnalysis) error {
+	return tp.executeReport(w, types.NewNodeReport(analysis))
+}
+
+// PrintUnusedImages evaluates the template against the unused image report
+func (tp *TemplatePrinter) PrintUnusedImages(w io.Writer, analysis *types.UnusedImageAnalysis) error {
+	return tp.executeReport(w, types.NewUnusedImageReport(analysis))
+}
+
+// PrintDiff evaluates the template against the image diff report
+func (tp *TemplatePrinter) PrintDiff(w io.Writer, diff *types.ImageDiff) error {
+	return tp.executeReport(w, types.NewImageDiffReport(diff))
+}
+
+// executeReport converts the report to its generic JSON form and evaluates the template.
+// Numbers are kept as json.Number so byte counts print as integers.
+func (tp *TemplatePrinter) executeReport(w io.Writer, report interface{}) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return fmt.Errorf("failed to decode report: %w", err)
+	}
+
+	if err := tp.execute(w, generic); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
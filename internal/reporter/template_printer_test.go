@@ -0,0 +1,123 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+func TestTemplatePrinter_Print(t *testing.T) {
+	analysis := &types.ImageAnalysis{
+		Images: []types.Image{
+			*types.NewImage("nginx:1.21", 133000000),
+			*types.NewImage("redis:6.2", 50000000),
+		},
+		TotalSize:  183000000,
+		UniqueSize: 183000000,
+	}
+
+	templateFile := filepath.Join(t.TempDir(), "report.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(`{{.kind}}: {{.summary.totalImages}} images`), 0o600))
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			name:     "go-template",
+			format:   "go-template={{range .images}}{{.name}} {{.size}}\n{{end}}",
+			expected: "nginx:1.21 133000000\nredis:6.2 50000000\n",
+		},
+		{
+			name:     "go-template-file",
+			format:   "go-template-file=" + templateFile,
+			expected: "ImageReport: 2 images",
+		},
+		{
+			name:     "jsonpath with braces",
+			format:   "jsonpath={.summary.totalSize}",
+			expected: "183000000",
+		},
+		{
+			name:     "jsonpath without braces",
+			format:   "jsonpath=.images[*].name",
+			expected: "nginx:1.21 redis:6.2",
+		},
+		{
+			name:     "jsonpath range",
+			format:   `jsonpath={range .images[*]}{.name}{"\t"}{.tag}{"\n"}{end}`,
+			expected: "nginx:1.21\t1.21\nredis:6.2\t6.2\n",
+		},
+		{
+			name:     "jsonpath missing key",
+			format:   "jsonpath={.workloads}",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer, err := NewTemplatePrinter(tt.format)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, printer.Print(&buf, analysis))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestTemplatePrinter_OtherReports(t *testing.T) {
+	printer, err := NewTemplatePrinter("jsonpath={.kind}")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, printer.PrintNodes(&buf, &types.NodeAnalysis{}))
+	assert.Equal(t, types.NodeReportKind, buf.String())
+
+	buf.Reset()
+	require.NoError(t, printer.PrintUnusedImages(&buf, &types.UnusedImageAnalysis{}))
+	assert.Equal(t, types.UnusedImageReportKind, buf.String())
+
+	buf.Reset()
+	require.NoError(t, printer.PrintDiff(&buf, &types.ImageDiff{}))
+	assert.Equal(t, types.ImageDiffReportKind, buf.String())
+}
+
+func TestNewTemplatePrinter_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		expectError string
+	}{
+		{name: "not a template format", format: "xml", expectError: "unsupported template format"},
+		{name: "empty go-template", format: "go-template=", expectError: "no template given"},
+		{name: "invalid go-template", format: "go-template={{.name", expectError: "failed to parse template"},
+		{name: "missing template file", format: "go-template-file=/does/not/exist", expectError: "failed to read template file"},
+		{name: "empty jsonpath", format: "jsonpath=", expectError: "no expression given"},
+		{name: "invalid jsonpath", format: "jsonpath={.images[}", expectError: "failed to parse jsonpath expression"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTemplatePrinter(tt.format)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectError)
+		})
+	}
+}
+
+func TestIsTemplateFormat(t *testing.T) {
+	assert.True(t, IsTemplateFormat("go-template={{.kind}}"))
+	assert.True(t, IsTemplateFormat("go-template-file=report.tmpl"))
+	assert.True(t, IsTemplateFormat("jsonpath={.kind}"))
+	assert.False(t, IsTemplateFormat("json"))
+	assert.False(t, IsTemplateFormat("go-template"))
+}
@@ -0,0 +1,52 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// YAMLPrinter formats output as YAML. It encodes the same report structures as
+// JSONPrinter, so field names and values are identical between the two formats.
+type YAMLPrinter struct{}
+
+// NewYAMLPrinter creates a new YAML printer
+func NewYAMLPrinter() *YAMLPrinter {
+	return &YAMLPrinter{}
+}
+
+// Print writes the analysis as YAML to the provided writer
+func (yp *YAMLPrinter) Print(w io.Writer, analysis *types.ImageAnalysis) error {
+	return writeYAML(w, types.NewImageReport(analysis))
+}
+
+// PrintNodes writes the per-node image footprint as YAML to the provided writer
+func (yp *YAMLPrinter) PrintNodes(w io.Writer, analysis *types.NodeAnalysis) error {
+	return writeYAML(w, types.NewNodeReport(analysis))
+}
+
+// PrintUnusedImages writes the unused images cached on nodes as YAML to the provided writer
+func (yp *YAMLPrinter) PrintUnusedImages(w io.Writer, analysis *types.UnusedImageAnalysis) error {
+	return writeYAML(w, types.NewUnusedImageReport(analysis))
+}
+
+// PrintDiff writes the differences between two analyses as YAML to the provided writer
+func (yp *YAMLPrinter) PrintDiff(w io.Writer, diff *types.ImageDiff) error {
+	return writeYAML(w, types.NewImageDiffReport(diff))
+}
+
+// writeYAML encodes the report as YAML through its JSON field names
+func writeYAML(w io.Writer, report interface{}) error {
+	data, err := yaml.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write YAML: %w", err)
+	}
+
+	return nil
+}
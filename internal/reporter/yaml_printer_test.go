@@ -0,0 +1,85 @@
+package reporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+func TestYAMLPrinter_MatchesJSON(t *testing.T) {
+	img := types.NewImage("nginx:1.21", 133000000)
+	img.SetNodes([]string{"node1", "node2"})
+	analysis := &types.ImageAnalysis{
+		Images:      []types.Image{*img, *types.NewInaccessibleImage("private/image:latest")},
+		TotalSize:   133000000,
+		UniqueSize:  133000000,
+		ClusterSize: 266000000,
+		Namespaces:  []types.NamespaceUsage{{Namespace: "default", ImageCount: 1, UniqueBytes: 133000000}},
+	}
+	nodes := &types.NodeAnalysis{
+		Nodes: []types.NodeUsage{{Name: "node1", ImageCount: 1, TotalBytes: 133000000}},
+	}
+	unused := &types.UnusedImageAnalysis{
+		Nodes: []types.NodeUnusedImages{{Node: "node1", Images: []types.UnusedImage{{Name: "old:v1", Size: 1}}, ReclaimableBytes: 1}},
+	}
+	diff := types.DiffAnalyses(&types.ImageAnalysis{}, analysis)
+
+	tests := []struct {
+		name      string
+		printJSON func(w *bytes.Buffer) error
+		printYAML func(w *bytes.Buffer) error
+	}{
+		{
+			name:      "image report",
+			printJSON: func(w *bytes.Buffer) error { return NewJSONPrinter().Print(w, analysis) },
+			printYAML: func(w *bytes.Buffer) error { return NewYAMLPrinter().Print(w, analysis) },
+		},
+		{
+			name:      "node report",
+			printJSON: func(w *bytes.Buffer) error { return NewJSONPrinter().PrintNodes(w, nodes) },
+			printYAML: func(w *bytes.Buffer) error { return NewYAMLPrinter().PrintNodes(w, nodes) },
+		},
+		{
+			name:      "unused image report",
+			printJSON: func(w *bytes.Buffer) error { return NewJSONPrinter().PrintUnusedImages(w, unused) },
+			printYAML: func(w *bytes.Buffer) error { return NewYAMLPrinter().PrintUnusedImages(w, unused) },
+		},
+		{
+			name:      "diff report",
+			printJSON: func(w *bytes.Buffer) error { return NewJSONPrinter().PrintDiff(w, diff) },
+			printYAML: func(w *bytes.Buffer) error { return NewYAMLPrinter().PrintDiff(w, diff) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jsonBuf, yamlBuf bytes.Buffer
+			require.NoError(t, tt.printJSON(&jsonBuf))
+			require.NoError(t, tt.printYAML(&yamlBuf))
+
+			converted, err := yaml.YAMLToJSON(yamlBuf.Bytes())
+			require.NoError(t, err, "output should be valid YAML")
+			assert.JSONEq(t, jsonBuf.String(), string(converted))
+		})
+	}
+}
+
+func TestYAMLPrinter_Print(t *testing.T) {
+	analysis := &types.ImageAnalysis{
+		Images:    []types.Image{*types.NewImage("redis:6.2", 50000000)},
+		TotalSize: 50000000,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewYAMLPrinter().Print(&buf, analysis))
+
+	output := buf.String()
+	assert.Contains(t, output, "apiVersion: analyze-images/v1\n")
+	assert.Contains(t, output, "kind: ImageReport\n")
+	assert.Contains(t, output, "  name: redis:6.2\n")
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -36,6 +39,11 @@ func NewClient(contextName string) (Interface, error) {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 
+	// Request protobuf, which is smaller on the wire and cheaper to decode than
+	// JSON for large pod and node lists. Custom resources only support JSON.
+	config.AcceptContentTypes = "application/vnd.kubernetes.protobuf,application/json"
+	config.ContentType = "application/vnd.kubernetes.protobuf"
+
 	// Create clientset
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -57,3 +65,38 @@ func (c *Client) ListPods(ctx context.Context, namespace string, opts metav1.Lis
 func (c *Client) ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
 	return c.clientset.CoreV1().Nodes().List(ctx, opts)
 }
+
+// ListReplicaSets lists replica sets in the given namespace with the given options.
+func (c *Client) ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error) {
+	return c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+}
+
+// ListJobs lists jobs in the given namespace with the given options.
+func (c *Client) ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (*batchv1.JobList, error) {
+	return c.clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+}
+
+// ListNamespaces lists namespaces in the cluster with the given options.
+func (c *Client) ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	return c.clientset.CoreV1().Namespaces().List(ctx, opts)
+}
+
+// WatchPods watches pods in the given namespace with the given options.
+func (c *Client) WatchPods(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.clientset.CoreV1().Pods(namespace).Watch(ctx, opts)
+}
+
+// WatchNodes watches nodes in the cluster with the given options.
+func (c *Client) WatchNodes(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.clientset.CoreV1().Nodes().Watch(ctx, opts)
+}
+
+// WatchReplicaSets watches replica sets in the given namespace with the given options.
+func (c *Client) WatchReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.clientset.AppsV1().ReplicaSets(namespace).Watch(ctx, opts)
+}
+
+// WatchJobs watches jobs in the given namespace with the given options.
+func (c *Client) WatchJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.clientset.BatchV1().Jobs(namespace).Watch(ctx, opts)
+}
@@ -3,9 +3,12 @@ package kubernetes
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -35,3 +38,44 @@ func (f *FakeClient) ListPods(ctx context.Context, namespace string, opts metav1
 func (f *FakeClient) ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
 	return f.clientset.CoreV1().Nodes().List(ctx, opts)
 }
+
+// ListReplicaSets lists replica sets in the given namespace with the given options.
+func (f *FakeClient) ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error) {
+	return f.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+}
+
+// ListJobs lists jobs in the given namespace with the given options.
+func (f *FakeClient) ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (*batchv1.JobList, error) {
+	return f.clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+}
+
+// ListNamespaces lists namespaces in the cluster with the given options.
+func (f *FakeClient) ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	return f.clientset.CoreV1().Namespaces().List(ctx, opts)
+}
+
+// WatchPods watches pods in the given namespace with the given options.
+func (f *FakeClient) WatchPods(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return f.clientset.CoreV1().Pods(namespace).Watch(ctx, opts)
+}
+
+// WatchNodes watches nodes in the cluster with the given options.
+func (f *FakeClient) WatchNodes(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return f.clientset.CoreV1().Nodes().Watch(ctx, opts)
+}
+
+// WatchReplicaSets watches replica sets in the given namespace with the given options.
+func (f *FakeClient) WatchReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return f.clientset.AppsV1().ReplicaSets(namespace).Watch(ctx, opts)
+}
+
+// WatchJobs watches jobs in the given namespace with the given options.
+func (f *FakeClient) WatchJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return f.clientset.BatchV1().Jobs(namespace).Watch(ctx, opts)
+}
+
+// Clientset returns the underlying fake clientset, so tests can change objects
+// after the client is created.
+func (f *FakeClient) Clientset() *fake.Clientset {
+	return f.clientset
+}
@@ -0,0 +1,205 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// errWatchUnsupported is returned by the watch methods of FileClient, since
+// snapshot files never change
+var errWatchUnsupported = fmt.Errorf("watch is not supported for snapshot files")
+
+// FileClient implements Interface by serving objects read from snapshot files,
+// allowing analysis without cluster access.
+type FileClient struct {
+	snapshot *Snapshot
+}
+
+// Compile-time assertion that FileClient implements Interface
+var _ Interface = (*FileClient)(nil)
+
+// NewFileClient creates a client that serves the pods, nodes, replica sets and jobs
+// read from the given files. Each file may be a snapshot written by the snapshot
+// subcommand or the output of `kubectl get pods,nodes -o json`.
+// Returns Interface, not *FileClient, to match the production constructor signature.
+func NewFileClient(paths ...string) (Interface, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no snapshot files given")
+	}
+
+	snapshot := &Snapshot{}
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot: %w", err)
+		}
+		snap, err := ReadSnapshot(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+		}
+		snapshot.merge(snap)
+	}
+
+	return NewSnapshotClient(snapshot), nil
+}
+
+// NewSnapshotClient creates a client that serves the objects in an in-memory snapshot.
+func NewSnapshotClient(snapshot *Snapshot) Interface {
+	return &FileClient{snapshot: snapshot}
+}
+
+// ListPods lists pods in the given namespace with the given options. Field
+// selectors support the same pod fields as the API server.
+func (f *FileClient) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	fieldSelector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid field selector: %w", err)
+	}
+
+	list := &corev1.PodList{}
+	for i := range f.snapshot.Pods {
+		pod := &f.snapshot.Pods[i]
+		if matches(pod, namespace, selector) && fieldSelector.Matches(podFields(pod)) {
+			list.Items = append(list.Items, f.snapshot.Pods[i])
+		}
+	}
+	return list, nil
+}
+
+// ListNodes lists nodes in the cluster with the given options.
+func (f *FileClient) ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	list := &corev1.NodeList{}
+	for i := range f.snapshot.Nodes {
+		if matches(&f.snapshot.Nodes[i], "", selector) {
+			list.Items = append(list.Items, f.snapshot.Nodes[i])
+		}
+	}
+	return list, nil
+}
+
+// ListReplicaSets lists replica sets in the given namespace with the given options.
+func (f *FileClient) ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	list := &appsv1.ReplicaSetList{}
+	for i := range f.snapshot.ReplicaSets {
+		if matches(&f.snapshot.ReplicaSets[i], namespace, selector) {
+			list.Items = append(list.Items, f.snapshot.ReplicaSets[i])
+		}
+	}
+	return list, nil
+}
+
+// ListJobs lists jobs in the given namespace with the given options.
+func (f *FileClient) ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (*batchv1.JobList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	list := &batchv1.JobList{}
+	for i := range f.snapshot.Jobs {
+		if matches(&f.snapshot.Jobs[i], namespace, selector) {
+			list.Items = append(list.Items, f.snapshot.Jobs[i])
+		}
+	}
+	return list, nil
+}
+
+// ListNamespaces lists namespaces with the given options. Snapshots without
+// namespace objects, such as `kubectl get pods -o json` output, list the
+// namespaces of their pods without labels.
+func (f *FileClient) ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	namespaces := f.snapshot.Namespaces
+	if len(namespaces) == 0 {
+		seen := make(map[string]bool)
+		for i := range f.snapshot.Pods {
+			name := f.snapshot.Pods[i].Namespace
+			if !seen[name] {
+				seen[name] = true
+				namespaces = append(namespaces, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+			}
+		}
+	}
+
+	list := &corev1.NamespaceList{}
+	for i := range namespaces {
+		if matches(&namespaces[i], "", selector) {
+			list.Items = append(list.Items, namespaces[i])
+		}
+	}
+	return list, nil
+}
+
+// WatchPods is not supported for snapshot files.
+func (f *FileClient) WatchPods(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, errWatchUnsupported
+}
+
+// WatchNodes is not supported for snapshot files.
+func (f *FileClient) WatchNodes(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, errWatchUnsupported
+}
+
+// WatchReplicaSets is not supported for snapshot files.
+func (f *FileClient) WatchReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, errWatchUnsupported
+}
+
+// WatchJobs is not supported for snapshot files.
+func (f *FileClient) WatchJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, errWatchUnsupported
+}
+
+// podFields returns the fields of a pod that field selectors can select on,
+// matching those supported by the API server
+func podFields(pod *corev1.Pod) fields.Set {
+	return fields.Set{
+		"metadata.name":            pod.Name,
+		"metadata.namespace":       pod.Namespace,
+		"spec.nodeName":            pod.Spec.NodeName,
+		"spec.restartPolicy":       string(pod.Spec.RestartPolicy),
+		"spec.schedulerName":       pod.Spec.SchedulerName,
+		"spec.serviceAccountName":  pod.Spec.ServiceAccountName,
+		"spec.hostNetwork":         strconv.FormatBool(pod.Spec.HostNetwork),
+		"status.phase":             string(pod.Status.Phase),
+		"status.podIP":             pod.Status.PodIP,
+		"status.nominatedNodeName": pod.Status.NominatedNodeName,
+	}
+}
+
+// matches reports whether obj is in the namespace (empty matches all) and has matching labels
+func matches(obj metav1.Object, namespace string, selector labels.Selector) bool {
+	if namespace != "" && obj.GetNamespace() != namespace {
+		return false
+	}
+	return selector.Matches(labels.Set(obj.GetLabels()))
+}
@@ -3,8 +3,11 @@ package kubernetes
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // Interface defines the contract for Kubernetes cluster operations.
@@ -12,4 +15,11 @@ import (
 type Interface interface {
 	ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error)
 	ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error)
+	ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error)
+	ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (*batchv1.JobList, error)
+	ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error)
+	WatchPods(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	WatchNodes(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	WatchReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	WatchJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
 }
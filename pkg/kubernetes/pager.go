@@ -0,0 +1,29 @@
+package kubernetes
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/pager"
+)
+
+// NewPager creates a pager that fetches pages of the given size and buffers at
+// most one page ahead, so only the page being processed and the next one are in
+// memory at a time
+func NewPager(pageFn pager.ListPageFunc, pageSize int64) *pager.ListPager {
+	p := pager.New(pageFn)
+	p.PageSize = pageSize
+	p.PageBufferSize = 1
+	return p
+}
+
+// ListOptions returns the options for listing objects in pages of the given size.
+// The watch cache ignores the limit of resourceVersion=0 lists and returns every
+// object at once, so only unpaginated lists are served from it; paginated lists
+// are read from etcd a page at a time.
+func ListOptions(pageSize int64) metav1.ListOptions {
+	if pageSize > 0 {
+		return metav1.ListOptions{}
+	}
+	return metav1.ListOptions{
+		ResourceVersion: "0", // Use watch cache for better performance
+	}
+}
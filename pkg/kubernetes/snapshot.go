@@ -0,0 +1,249 @@
+package kubernetes
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/pager"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// lastAppliedAnnotation is dropped from snapshot objects to keep files small
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Snapshot holds the cluster objects needed to analyze images offline
+type Snapshot struct {
+	Pods        []corev1.Pod
+	Nodes       []corev1.Node
+	ReplicaSets []appsv1.ReplicaSet
+	Jobs        []batchv1.Job
+	Namespaces  []corev1.Namespace
+}
+
+// TakeSnapshot lists pods, replica sets and jobs in the given namespace (empty for all)
+// and all nodes and namespaces through the given client.
+func TakeSnapshot(ctx context.Context, c Interface, namespace string) (*Snapshot, error) {
+	return TakeSnapshotIn(ctx, c, []string{namespace})
+}
+
+// TakeSnapshotIn lists pods, replica sets and jobs in the given namespaces (none, or
+// an empty namespace, for all) and all nodes and namespaces through the given client.
+// Namespaces are only needed for namespace selectors, so they are left out when
+// listing them is forbidden. Objects are listed in pages of the default analysis
+// page sizes.
+func TakeSnapshotIn(ctx context.Context, c Interface, namespaces []string) (*Snapshot, error) {
+	if len(namespaces) == 0 || slices.Contains(namespaces, "") {
+		namespaces = []string{""}
+	}
+	// Each namespace is listed once, whatever the order or repetition of the list
+	namespaces = slices.Compact(slices.Sorted(slices.Values(namespaces)))
+
+	snap := &Snapshot{}
+	for _, namespace := range namespaces {
+		err := listAll(ctx, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return c.ListPods(ctx, namespace, opts)
+		}, types.DefaultPodPageSize, func(obj runtime.Object) {
+			snap.Pods = append(snap.Pods, *obj.(*corev1.Pod))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		err = listAll(ctx, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return c.ListReplicaSets(ctx, namespace, opts)
+		}, types.DefaultOwnerPageSize, func(obj runtime.Object) {
+			snap.ReplicaSets = append(snap.ReplicaSets, *obj.(*appsv1.ReplicaSet))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list replica sets: %w", err)
+		}
+
+		err = listAll(ctx, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return c.ListJobs(ctx, namespace, opts)
+		}, types.DefaultOwnerPageSize, func(obj runtime.Object) {
+			snap.Jobs = append(snap.Jobs, *obj.(*batchv1.Job))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list jobs: %w", err)
+		}
+	}
+
+	err := listAll(ctx, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.ListNodes(ctx, opts)
+	}, types.DefaultNodePageSize, func(obj runtime.Object) {
+		snap.Nodes = append(snap.Nodes, *obj.(*corev1.Node))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	err = listAll(ctx, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.ListNamespaces(ctx, opts)
+	}, types.DefaultNamespacePageSize, func(obj runtime.Object) {
+		snap.Namespaces = append(snap.Namespaces, *obj.(*corev1.Namespace))
+	})
+	if err != nil && !apierrors.IsForbidden(err) {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	return snap, nil
+}
+
+// listAll pages through a list call in pages of the given size, passing every
+// item to fn. Paging and resource version rules match the analysis client.
+func listAll(ctx context.Context, list pager.ListPageFunc, pageSize int64, fn func(runtime.Object)) error {
+	return NewPager(list, pageSize).EachListItem(ctx, ListOptions(pageSize), func(obj runtime.Object) error {
+		fn(obj)
+		return nil
+	})
+}
+
+// Write encodes the snapshot as a gzip-compressed v1 List, the same shape as
+// `kubectl get pods,nodes -o json`
+func (s *Snapshot) Write(w io.Writer) error {
+	list := &corev1.List{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+	}
+
+	add := func(obj runtime.Object, meta *metav1.ObjectMeta, apiVersion, kind string) error {
+		// Drop server-side bookkeeping that analysis never reads
+		meta.ManagedFields = nil
+		delete(meta.Annotations, lastAppliedAnnotation)
+
+		obj.GetObjectKind().SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, kind))
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s %s: %w", kind, meta.Name, err)
+		}
+		list.Items = append(list.Items, runtime.RawExtension{Raw: raw})
+		return nil
+	}
+
+	for i := range s.Pods {
+		pod := s.Pods[i].DeepCopy()
+		if err := add(pod, &pod.ObjectMeta, "v1", "Pod"); err != nil {
+			return err
+		}
+	}
+	for i := range s.Nodes {
+		node := s.Nodes[i].DeepCopy()
+		if err := add(node, &node.ObjectMeta, "v1", "Node"); err != nil {
+			return err
+		}
+	}
+	for i := range s.ReplicaSets {
+		rs := s.ReplicaSets[i].DeepCopy()
+		if err := add(rs, &rs.ObjectMeta, "apps/v1", "ReplicaSet"); err != nil {
+			return err
+		}
+	}
+	for i := range s.Jobs {
+		job := s.Jobs[i].DeepCopy()
+		if err := add(job, &job.ObjectMeta, "batch/v1", "Job"); err != nil {
+			return err
+		}
+	}
+	for i := range s.Namespaces {
+		ns := s.Namespaces[i].DeepCopy()
+		if err := add(ns, &ns.ObjectMeta, "v1", "Namespace"); err != nil {
+			return err
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(list); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return gz.Close()
+}
+
+// ReadSnapshot decodes a snapshot written by Write, or any JSON List, PodList,
+// NodeList, or single object as printed by kubectl. Gzip compression is detected
+// automatically; object kinds not needed for analysis are ignored.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	br := bufio.NewReader(r)
+
+	// Detect gzip compression from the magic header
+	var src io.Reader = br
+	if header, err := br.Peek(2); err == nil && header[0] == 0x1f && header[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip: %w", err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{}
+	if err := snap.decode(data); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// decode adds the object encoded in data, recursing into lists
+func (s *Snapshot) decode(data []byte) error {
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decode object: %w", err)
+	}
+
+	switch o := obj.(type) {
+	case *corev1.List:
+		for _, item := range o.Items {
+			if err := s.decode(item.Raw); err != nil {
+				return err
+			}
+		}
+	case *corev1.PodList:
+		s.Pods = append(s.Pods, o.Items...)
+	case *corev1.NodeList:
+		s.Nodes = append(s.Nodes, o.Items...)
+	case *appsv1.ReplicaSetList:
+		s.ReplicaSets = append(s.ReplicaSets, o.Items...)
+	case *batchv1.JobList:
+		s.Jobs = append(s.Jobs, o.Items...)
+	case *corev1.NamespaceList:
+		s.Namespaces = append(s.Namespaces, o.Items...)
+	case *corev1.Pod:
+		s.Pods = append(s.Pods, *o)
+	case *corev1.Node:
+		s.Nodes = append(s.Nodes, *o)
+	case *appsv1.ReplicaSet:
+		s.ReplicaSets = append(s.ReplicaSets, *o)
+	case *batchv1.Job:
+		s.Jobs = append(s.Jobs, *o)
+	case *corev1.Namespace:
+		s.Namespaces = append(s.Namespaces, *o)
+	}
+
+	return nil
+}
+
+// merge appends the objects of other to the snapshot
+func (s *Snapshot) merge(other *Snapshot) {
+	s.Pods = append(s.Pods, other.Pods...)
+	s.Nodes = append(s.Nodes, other.Nodes...)
+	s.ReplicaSets = append(s.ReplicaSets, other.ReplicaSets...)
+	s.Jobs = append(s.Jobs, other.Jobs...)
+	s.Namespaces = append(s.Namespaces, other.Namespaces...)
+}
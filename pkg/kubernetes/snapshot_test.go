@@ -0,0 +1,216 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web-1",
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "web"},
+			Annotations: map[string]string{lastAppliedAnnotation: "{}"},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl"},
+			},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx:1.21"}}},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: corev1.NodeStatus{Images: []corev1.ContainerImage{
+			{Names: []string{"nginx:1.21"}, SizeBytes: 100},
+		}},
+	}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default"}}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "web"}}}
+
+	snap, err := TakeSnapshot(context.Background(), NewFakeClient(pod, node, rs, ns), "")
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, snap.Write(buf))
+
+	// Output is gzip-compressed
+	require.True(t, buf.Len() > 2)
+	assert.Equal(t, []byte{0x1f, 0x8b}, buf.Bytes()[:2])
+
+	read, err := ReadSnapshot(buf)
+	require.NoError(t, err)
+	require.Len(t, read.Pods, 1)
+	require.Len(t, read.Nodes, 1)
+	require.Len(t, read.ReplicaSets, 1)
+	assert.Empty(t, read.Jobs)
+	require.Len(t, read.Namespaces, 1)
+	assert.Equal(t, "web", read.Namespaces[0].Labels["team"])
+
+	// Namespaces are served with their labels for namespace selectors
+	namespaces, err := NewSnapshotClient(read).ListNamespaces(context.Background(), metav1.ListOptions{LabelSelector: "team=web"})
+	require.NoError(t, err)
+	require.Len(t, namespaces.Items, 1)
+	assert.Equal(t, "default", namespaces.Items[0].Name)
+
+	assert.Equal(t, "nginx:1.21", read.Pods[0].Spec.Containers[0].Image)
+	assert.Equal(t, int64(100), read.Nodes[0].Status.Images[0].SizeBytes)
+	assert.Empty(t, read.Pods[0].ManagedFields)
+	assert.NotContains(t, read.Pods[0].Annotations, lastAppliedAnnotation)
+
+	// Source objects are not modified
+	assert.Len(t, snap.Pods[0].ManagedFields, 1)
+}
+
+func TestTakeSnapshotIn_Namespaces(t *testing.T) {
+	pod := func(name, namespace string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	client := NewFakeClient(pod("web", "team-a"), pod("api", "team-b"), pod("db", "team-c"), node)
+
+	// Repeated namespaces are listed once
+	snap, err := TakeSnapshotIn(context.Background(), client, []string{"team-b", "team-a", "team-b"})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(snap.Pods))
+	for _, p := range snap.Pods {
+		names = append(names, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"web", "api"}, names)
+	assert.Len(t, snap.Nodes, 1)
+
+	// No namespaces lists all of them
+	snap, err = TakeSnapshotIn(context.Background(), client, nil)
+	require.NoError(t, err)
+	assert.Len(t, snap.Pods, 3)
+}
+
+func TestListAll_Paginated(t *testing.T) {
+	// Two pages of pods, linked by a continue token
+	pages := []*corev1.PodList{
+		{ListMeta: metav1.ListMeta{Continue: "next"}, Items: []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "p1"}}}},
+		{Items: []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "p2"}}}},
+	}
+	var calls []metav1.ListOptions
+	list := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		calls = append(calls, opts)
+		return pages[len(calls)-1], nil
+	}
+
+	var names []string
+	err := listAll(context.Background(), list, 1, func(obj runtime.Object) {
+		names = append(names, obj.(*corev1.Pod).Name)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"p1", "p2"}, names)
+	require.Len(t, calls, 2)
+	for _, opts := range calls {
+		assert.Equal(t, int64(1), opts.Limit)
+		// Paginated lists are not served from the watch cache, which ignores the limit
+		assert.Empty(t, opts.ResourceVersion)
+	}
+	assert.Equal(t, "next", calls[1].Continue)
+}
+
+func TestReadSnapshot_KubectlOutput(t *testing.T) {
+	// Shape of `kubectl get pods,nodes -o json`
+	data := `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [
+    {"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "p1", "namespace": "default"},
+     "spec": {"containers": [{"name": "c", "image": "redis:6.2"}]}},
+    {"apiVersion": "v1", "kind": "Node", "metadata": {"name": "n1"},
+     "status": {"images": [{"names": ["redis:6.2"], "sizeBytes": 50}]}},
+    {"apiVersion": "v1", "kind": "Service", "metadata": {"name": "ignored", "namespace": "default"}}
+  ]
+}`
+
+	snap, err := ReadSnapshot(bytes.NewBufferString(data))
+	require.NoError(t, err)
+	require.Len(t, snap.Pods, 1)
+	require.Len(t, snap.Nodes, 1)
+	assert.Equal(t, "p1", snap.Pods[0].Name)
+	assert.Equal(t, "n1", snap.Nodes[0].Name)
+}
+
+func TestReadSnapshot_InvalidData(t *testing.T) {
+	_, err := ReadSnapshot(bytes.NewBufferString("not json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode object")
+}
+
+func TestFileClient(t *testing.T) {
+	dir := t.TempDir()
+	podsFile := filepath.Join(dir, "pods.json")
+	nodesFile := filepath.Join(dir, "nodes.json")
+
+	require.NoError(t, os.WriteFile(podsFile, []byte(`{"apiVersion": "v1", "kind": "PodList", "items": [
+  {"metadata": {"name": "web", "namespace": "prod", "labels": {"app": "web"}}, "spec": {"nodeName": "node1"}, "status": {"phase": "Running"}},
+  {"metadata": {"name": "db", "namespace": "prod", "labels": {"app": "db"}}, "status": {"phase": "Pending"}},
+  {"metadata": {"name": "tool", "namespace": "dev"}, "spec": {"nodeName": "node1"}, "status": {"phase": "Succeeded"}}
+]}`), 0o600))
+	require.NoError(t, os.WriteFile(nodesFile, []byte(`{"apiVersion": "v1", "kind": "NodeList", "items": [
+  {"metadata": {"name": "node1"}}
+]}`), 0o600))
+
+	client, err := NewFileClient(podsFile, nodesFile)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	all, err := client.ListPods(ctx, "", metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, all.Items, 3)
+
+	prod, err := client.ListPods(ctx, "prod", metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, prod.Items, 2)
+
+	web, err := client.ListPods(ctx, "prod", metav1.ListOptions{LabelSelector: "app=web"})
+	require.NoError(t, err)
+	require.Len(t, web.Items, 1)
+	assert.Equal(t, "web", web.Items[0].Name)
+
+	running, err := client.ListPods(ctx, "", metav1.ListOptions{FieldSelector: "spec.nodeName=node1,status.phase!=Succeeded"})
+	require.NoError(t, err)
+	require.Len(t, running.Items, 1)
+	assert.Equal(t, "web", running.Items[0].Name)
+
+	_, err = client.ListPods(ctx, "", metav1.ListOptions{FieldSelector: "status.phase"})
+	assert.Error(t, err)
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, nodes.Items, 1)
+
+	// Without namespace objects, the namespaces of the pods are listed without labels
+	namespaces, err := client.ListNamespaces(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, namespaces.Items, 2)
+	assert.Equal(t, "prod", namespaces.Items[0].Name)
+	assert.Equal(t, "dev", namespaces.Items[1].Name)
+
+	_, err = client.ListPods(ctx, "", metav1.ListOptions{LabelSelector: "app in ("})
+	assert.Error(t, err)
+}
+
+func TestNewFileClient_Errors(t *testing.T) {
+	_, err := NewFileClient()
+	assert.Error(t, err)
+
+	_, err = NewFileClient(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open snapshot")
+}
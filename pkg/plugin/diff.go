@@ -0,0 +1,143 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/analyzer"
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/reporter"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// DiffOptions holds all the configuration and dependencies for comparing two
+// analyses. It follows the kubectl plugin Complete/Validate/Run pattern.
+type DiffOptions struct {
+	// CLI arguments and flags
+	OldFile      string
+	NewFile      string
+	OutputFormat string
+	NoColor      bool
+	TopImages    int
+
+	// Injected dependencies
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// Complete populates defaults for unset fields and reads the two file arguments.
+func (o *DiffOptions) Complete(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 arguments (old and new report or snapshot), got %d", len(args))
+	}
+	o.OldFile = args[0]
+	o.NewFile = args[1]
+
+	// Set defaults for unset fields
+	if o.OutputFormat == "" {
+		o.OutputFormat = "table"
+	}
+	if o.TopImages == 0 {
+		o.TopImages = 25
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	if o.ErrOut == nil {
+		o.ErrOut = os.Stderr
+	}
+
+	return nil
+}
+
+// Validate checks that all options have valid values.
+func (o *DiffOptions) Validate() error {
+	// Validate output format
+	switch o.OutputFormat {
+	case "table", "json", "yaml":
+		// valid
+	default:
+		if !reporter.IsTemplateFormat(o.OutputFormat) {
+			return fmt.Errorf("invalid output format %q: must be \"table\", \"json\", \"yaml\", go-template=..., go-template-file=... or jsonpath=...", o.OutputFormat)
+		}
+		if _, err := reporter.NewTemplatePrinter(o.OutputFormat); err != nil {
+			return fmt.Errorf("invalid output format: %w", err)
+		}
+	}
+
+	// Validate top images count
+	if o.TopImages < 1 {
+		return fmt.Errorf("--top-images must be at least 1, got %d", o.TopImages)
+	}
+
+	return nil
+}
+
+// Run loads both analyses, compares them, and generates the diff report.
+func (o *DiffOptions) Run(ctx context.Context) error {
+	oldAnalysis, err := loadAnalysis(ctx, o.OldFile)
+	if err != nil {
+		return err
+	}
+	newAnalysis, err := loadAnalysis(ctx, o.NewFile)
+	if err != nil {
+		return err
+	}
+
+	diff := types.DiffAnalyses(oldAnalysis, newAnalysis)
+
+	// Generate report
+	rep := reporter.NewReporter(o.OutputFormat)
+	rep.SetNoColor(o.NoColor)
+	rep.SetTopImages(o.TopImages)
+	if err := rep.GenerateDiffReportTo(o.Out, diff); err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	return nil
+}
+
+// loadAnalysis reads an image analysis from a report written with -o json or -o yaml,
+// or analyzes a snapshot (or kubectl JSON output) with images attributed to namespaces
+func loadAnalysis(ctx context.Context, path string) (*types.ImageAnalysis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// JSON is valid YAML, so both report formats convert to the same JSON
+	var header types.ReportHeader
+	reportJSON, err := yaml.YAMLToJSON(data)
+	if err == nil && json.Unmarshal(reportJSON, &header) == nil && header.Kind == types.ImageReportKind {
+		if header.APIVersion != types.ReportAPIVersion {
+			return nil, fmt.Errorf("unsupported report version %q in %s: expected %q", header.APIVersion, path, types.ReportAPIVersion)
+		}
+		var report types.ImageReport
+		if err := json.Unmarshal(reportJSON, &report); err != nil {
+			return nil, fmt.Errorf("failed to decode report %s: %w", path, err)
+		}
+		return report.Analysis(), nil
+	}
+
+	snapshot, err := kubernetes.ReadSnapshot(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s as a report or snapshot: %w", path, err)
+	}
+
+	config := types.DefaultAnalysisConfig()
+	config.GroupBy = types.GroupByNamespace
+	clusterClient := cluster.NewClient(kubernetes.NewSnapshotClient(snapshot))
+	analysis, err := analyzer.NewPodAnalyzer(clusterClient, config).AnalyzePods(ctx, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze snapshot %s: %w", path, err)
+	}
+
+	return analysis, nil
+}
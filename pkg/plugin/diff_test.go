@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+)
+
+// writeReport runs the JSON image analysis against objects and saves it to a file
+func writeReport(t *testing.T, path string, objects ...runtime.Object) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	o := &AnalyzeOptions{
+		OutputFormat:     "json",
+		GroupBy:          "namespace",
+		KubernetesClient: kubernetes.NewFakeClient(objects...),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Run(context.Background()))
+	require.NoError(t, os.WriteFile(path, out.Bytes(), 0o600))
+}
+
+// writeSnapshot exports objects to a snapshot file
+func writeSnapshot(t *testing.T, path string, objects ...runtime.Object) {
+	t.Helper()
+	so := &SnapshotOptions{
+		OutputFile:       path,
+		KubernetesClient: kubernetes.NewFakeClient(objects...),
+		Out:              &bytes.Buffer{},
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, so.Complete())
+	require.NoError(t, so.Run(context.Background()))
+}
+
+func TestDiffOptions_Complete(t *testing.T) {
+	o := &DiffOptions{}
+	require.NoError(t, o.Complete([]string{"old.json", "new.json"}))
+	assert.Equal(t, "old.json", o.OldFile)
+	assert.Equal(t, "new.json", o.NewFile)
+	assert.Equal(t, "table", o.OutputFormat)
+	assert.Equal(t, 25, o.TopImages)
+
+	err := (&DiffOptions{}).Complete([]string{"only-one.json"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected 2 arguments")
+}
+
+func TestDiffOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        DiffOptions
+		expectError string
+	}{
+		{name: "valid table format", opts: DiffOptions{OutputFormat: "table", TopImages: 25}},
+		{name: "valid json format", opts: DiffOptions{OutputFormat: "json", TopImages: 25}},
+		{name: "valid yaml format", opts: DiffOptions{OutputFormat: "yaml", TopImages: 25}},
+		{name: "invalid output format", opts: DiffOptions{OutputFormat: "xml", TopImages: 25}, expectError: "invalid output format"},
+		{name: "topImages zero", opts: DiffOptions{OutputFormat: "table", TopImages: 0}, expectError: "must be at least 1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDiffOptions_Run_ReportAndSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.json")
+	newPath := filepath.Join(dir, "new.json.gz")
+
+	writeReport(t, oldPath,
+		testPod("web", "shop", "gcr.io/shop/web:v1"),
+		testNode("node1", map[string]int64{"gcr.io/shop/web:v1": 100000000}),
+	)
+	writeSnapshot(t, newPath,
+		testPod("web", "shop", "gcr.io/shop/web:v2"),
+		testPod("cache", "shop", "redis:6.2"),
+		testNode("node1", map[string]int64{"gcr.io/shop/web:v2": 400000000, "redis:6.2": 50000000}),
+	)
+
+	out := &bytes.Buffer{}
+	o := &DiffOptions{OutputFormat: "json", Out: out, ErrOut: &bytes.Buffer{}}
+	require.NoError(t, o.Complete([]string{oldPath, newPath}))
+	require.NoError(t, o.Validate())
+	require.NoError(t, o.Run(context.Background()))
+
+	var result struct {
+		Kind    string `json:"kind"`
+		Summary struct {
+			OldUniqueSize int64 `json:"oldUniqueSize"`
+			NewUniqueSize int64 `json:"newUniqueSize"`
+		} `json:"summary"`
+		Added []struct {
+			Name string `json:"name"`
+		} `json:"added"`
+		Repositories []struct {
+			Repository string `json:"repository"`
+		} `json:"repositories"`
+		Namespaces []struct {
+			Name string `json:"name"`
+		} `json:"namespaces"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+
+	assert.Equal(t, "ImageDiffReport", result.Kind)
+	assert.Equal(t, int64(100000000), result.Summary.OldUniqueSize)
+	assert.Equal(t, int64(450000000), result.Summary.NewUniqueSize)
+	assert.Len(t, result.Added, 2)
+	require.Len(t, result.Repositories, 1)
+	assert.Equal(t, "gcr.io/shop/web", result.Repositories[0].Repository)
+	require.Len(t, result.Namespaces, 1)
+	assert.Equal(t, "shop", result.Namespaces[0].Name)
+}
+
+func TestDiffOptions_Run_UnsupportedVersion(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.json")
+	require.NoError(t, os.WriteFile(oldPath, []byte(`{"apiVersion": "analyze-images/v0", "kind": "ImageReport"}`), 0o600))
+
+	o := &DiffOptions{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}}
+	require.NoError(t, o.Complete([]string{oldPath, oldPath}))
+	err := o.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported report version")
+}
+
+func TestDiffOptions_Run_YAMLReports(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{filepath.Join(dir, "old.yaml"), filepath.Join(dir, "new.yaml")}
+	images := []string{"nginx:1.21", "nginx:1.25"}
+
+	for i, path := range paths {
+		out := &bytes.Buffer{}
+		o := &AnalyzeOptions{
+			OutputFormat:     "yaml",
+			KubernetesClient: kubernetes.NewFakeClient(testNode("node1", map[string]int64{images[i]: int64(i+1) * 100000000})),
+			Out:              out,
+			ErrOut:           &bytes.Buffer{},
+		}
+		require.NoError(t, o.Complete())
+		require.NoError(t, o.Run(context.Background()))
+		require.NoError(t, os.WriteFile(path, out.Bytes(), 0o600))
+	}
+
+	out := &bytes.Buffer{}
+	o := &DiffOptions{NoColor: true, Out: out, ErrOut: &bytes.Buffer{}}
+	require.NoError(t, o.Complete(paths))
+	require.NoError(t, o.Run(context.Background()))
+
+	output := out.String()
+	assert.Contains(t, output, "Repository Size Changes")
+	assert.Contains(t, output, "docker.io/library/nginx")
+	assert.Contains(t, output, "+95.4 MB")
+}
@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/analyzer"
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/reporter"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+)
+
+// NodesOptions holds all the configuration and dependencies for the per-node
+// image footprint report. It follows the kubectl plugin Complete/Validate/Run pattern.
+type NodesOptions struct {
+	// CLI flags
+	OutputFormat string
+	NoColor      bool
+	KubeContext  string
+	FromFiles    []string
+
+	// Injected dependencies
+	KubernetesClient kubernetes.Interface
+	Out              io.Writer
+	ErrOut           io.Writer
+}
+
+// Complete populates defaults for unset fields and creates the kubernetes client
+// if one has not been injected. Tests can pre-inject a FakeClient to skip creation.
+func (o *NodesOptions) Complete() error {
+	// Set defaults for unset fields
+	if o.OutputFormat == "" {
+		o.OutputFormat = "table"
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	if o.ErrOut == nil {
+		o.ErrOut = os.Stderr
+	}
+
+	// Create kubernetes client if not injected (production path)
+	if o.KubernetesClient == nil {
+		k8sClient, err := newKubernetesClient(o.KubeContext, o.FromFiles)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+		o.KubernetesClient = k8sClient
+	}
+
+	return nil
+}
+
+// Validate checks that all options have valid values.
+func (o *NodesOptions) Validate() error {
+	if err := validateSource(o.KubeContext, o.FromFiles); err != nil {
+		return err
+	}
+
+	// Validate output format
+	switch o.OutputFormat {
+	case "table", "json", "yaml":
+		// valid
+	default:
+		if !reporter.IsTemplateFormat(o.OutputFormat) {
+			return fmt.Errorf("invalid output format %q: must be \"table\", \"json\", \"yaml\", go-template=..., go-template-file=... or jsonpath=...", o.OutputFormat)
+		}
+		if _, err := reporter.NewTemplatePrinter(o.OutputFormat); err != nil {
+			return fmt.Errorf("invalid output format: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Run analyzes the image disk footprint of every node and generates the report.
+func (o *NodesOptions) Run(ctx context.Context) error {
+	// Create cluster client with injected kubernetes interface
+	clusterClient := cluster.NewClient(o.KubernetesClient)
+
+	// Create analyzer with injected cluster client
+	nodeAnalyzer := analyzer.NewNodeAnalyzer(clusterClient)
+
+	// Run analysis
+	analysis, err := nodeAnalyzer.AnalyzeNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to analyze nodes: %w", err)
+	}
+
+	// Generate report
+	rep := reporter.NewReporter(o.OutputFormat)
+	rep.SetNoColor(o.NoColor)
+	if err := rep.GenerateNodeReportTo(o.Out, analysis); err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	return nil
+}
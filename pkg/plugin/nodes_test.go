@@ -0,0 +1,104 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+)
+
+func TestNodesOptions_Complete(t *testing.T) {
+	o := &NodesOptions{
+		KubernetesClient: kubernetes.NewFakeClient(), // pre-inject to avoid kubeconfig requirement
+	}
+	err := o.Complete()
+	require.NoError(t, err)
+	assert.Equal(t, "table", o.OutputFormat)
+	assert.NotNil(t, o.Out)
+	assert.NotNil(t, o.ErrOut)
+}
+
+func TestNodesOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        NodesOptions
+		expectError string
+	}{
+		{name: "valid table format", opts: NodesOptions{OutputFormat: "table"}},
+		{name: "valid json format", opts: NodesOptions{OutputFormat: "json"}},
+		{name: "invalid output format", opts: NodesOptions{OutputFormat: "xml"}, expectError: "invalid output format"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNodesOptions_Run_TableOutput(t *testing.T) {
+	pod := testPod("pod1", "default", "nginx:1.21")
+	pod.Spec.NodeName = "node1"
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"old:v1":     50000000,
+	})
+
+	out := &bytes.Buffer{}
+
+	o := &NodesOptions{
+		OutputFormat:     "table",
+		NoColor:          true,
+		KubernetesClient: kubernetes.NewFakeClient(pod, node),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+
+	err := o.Run(context.Background())
+	require.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "Image Disk Footprint by Node")
+	assert.Contains(t, output, "node1")
+}
+
+func TestNodesOptions_Run_JSONOutput(t *testing.T) {
+	pod := testPod("pod1", "default", "nginx:1.21")
+	pod.Spec.NodeName = "node1"
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"old:v1":     50000000,
+	})
+
+	out := &bytes.Buffer{}
+
+	o := &NodesOptions{
+		OutputFormat:     "json",
+		KubernetesClient: kubernetes.NewFakeClient(pod, node),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+
+	err := o.Run(context.Background())
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	err = json.Unmarshal(out.Bytes(), &result)
+	require.NoError(t, err, "failed to parse JSON output")
+
+	summary, ok := result["summary"].(map[string]interface{})
+	require.True(t, ok, "expected summary object in JSON")
+	assert.Equal(t, float64(1), summary["totalNodes"])
+	assert.Equal(t, float64(50000000), summary["unreferencedSize"])
+}
@@ -5,6 +5,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/ronaknnathani/kubectl-analyze-images/internal/analyzer"
 	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
@@ -17,18 +23,51 @@ import (
 // It follows the kubectl plugin Complete/Validate/Run pattern.
 type AnalyzeOptions struct {
 	// CLI flags
-	Namespace     string
-	LabelSelector string
-	OutputFormat  string
-	NoColor       bool
-	TopImages     int
-	KubeContext   string
-	ShowHistogram bool
+	Namespaces        []string
+	ExcludeNamespaces []string
+	NamespaceSelector string
+	LabelSelector     string
+	FieldSelector     string
+	PodPhases         []string
+	Nodes             []string
+	NodeSelector      string
+	OutputFormat      string
+	NoColor           bool
+	TopImages         int
+	KubeContext       string
+	FromFiles         []string
+	ShowHistogram     bool
+	GroupBy           string
+	ContainerTypes    []string
+	SortBy            string
+	Columns           []string
+	TagHygiene        bool
+	TagDrift          bool
+	Watch             bool
+	WatchInterval     time.Duration
+	PodPageSize       int64
+	NodePageSize      int64
+	Unpaginated       bool
+
+	// Policy flags; flags override the corresponding fields of the policy file
+	PolicyFile        string
+	MaxImageSize      string
+	NamespaceBudget   string
+	DisallowLatestTag bool
+	AllowedRegistries []string
+
+	// Namespace is the single target namespace.
+	//
+	// Deprecated: use Namespaces. Complete adds it to Namespaces.
+	Namespace string
 
 	// Injected dependencies
 	KubernetesClient kubernetes.Interface
 	Out              io.Writer
 	ErrOut           io.Writer
+
+	// Resolved in Complete from the policy file and flags
+	policy *types.Policy
 }
 
 // Complete populates defaults for unset fields and creates the kubernetes client
@@ -41,6 +80,26 @@ func (o *AnalyzeOptions) Complete() error {
 	if o.TopImages == 0 {
 		o.TopImages = 25
 	}
+	if o.SortBy == "" {
+		o.SortBy = types.SortBySize
+	}
+	if o.WatchInterval == 0 {
+		o.WatchInterval = 2 * time.Second
+	}
+	o.Namespaces = foldNamespace(o.Namespaces, o.Namespace)
+
+	// Listing everything in one request is an explicit opt-in
+	if o.Unpaginated {
+		o.PodPageSize = 0
+		o.NodePageSize = 0
+	} else {
+		if o.PodPageSize == 0 {
+			o.PodPageSize = types.DefaultPodPageSize
+		}
+		if o.NodePageSize == 0 {
+			o.NodePageSize = types.DefaultNodePageSize
+		}
+	}
 	if o.Out == nil {
 		o.Out = os.Stdout
 	}
@@ -48,9 +107,13 @@ func (o *AnalyzeOptions) Complete() error {
 		o.ErrOut = os.Stderr
 	}
 
+	if err := o.completePolicy(); err != nil {
+		return err
+	}
+
 	// Create kubernetes client if not injected (production path)
 	if o.KubernetesClient == nil {
-		k8sClient, err := kubernetes.NewClient(o.KubeContext)
+		k8sClient, err := newKubernetesClient(o.KubeContext, o.FromFiles)
 		if err != nil {
 			return fmt.Errorf("failed to create kubernetes client: %w", err)
 		}
@@ -60,14 +123,91 @@ func (o *AnalyzeOptions) Complete() error {
 	return nil
 }
 
+// completePolicy loads the policy file, if any, and applies the policy flags on top of it
+func (o *AnalyzeOptions) completePolicy() error {
+	policy := &types.Policy{}
+	if o.PolicyFile != "" {
+		var err error
+		if policy, err = loadPolicy(o.PolicyFile); err != nil {
+			return err
+		}
+	}
+
+	if o.MaxImageSize != "" {
+		q, err := parseQuantity("--max-image-size", o.MaxImageSize)
+		if err != nil {
+			return err
+		}
+		policy.MaxImageSize = q
+	}
+	if o.NamespaceBudget != "" {
+		q, err := parseQuantity("--namespace-budget", o.NamespaceBudget)
+		if err != nil {
+			return err
+		}
+		policy.NamespaceBudget = q
+	}
+	if o.DisallowLatestTag {
+		policy.DisallowLatestTag = true
+	}
+	if len(o.AllowedRegistries) > 0 {
+		policy.AllowedRegistries = o.AllowedRegistries
+	}
+
+	o.policy = policy
+	return nil
+}
+
 // Validate checks that all options have valid values.
 func (o *AnalyzeOptions) Validate() error {
+	if err := validateSource(o.KubeContext, o.FromFiles); err != nil {
+		return err
+	}
+
 	// Validate output format
 	switch o.OutputFormat {
-	case "table", "json":
+	case "table", "json", "yaml", "csv", "tsv", "html", "markdown":
 		// valid
 	default:
-		return fmt.Errorf("invalid output format %q: must be \"table\" or \"json\"", o.OutputFormat)
+		if !reporter.IsTemplateFormat(o.OutputFormat) {
+			return fmt.Errorf("invalid output format %q: must be \"table\", \"json\", \"yaml\", \"csv\", \"tsv\", \"html\", \"markdown\", go-template=..., go-template-file=... or jsonpath=...", o.OutputFormat)
+		}
+		if _, err := reporter.NewTemplatePrinter(o.OutputFormat); err != nil {
+			return fmt.Errorf("invalid output format: %w", err)
+		}
+	}
+
+	// Validate columns
+	if len(o.Columns) > 0 {
+		if o.OutputFormat != "csv" && o.OutputFormat != "tsv" {
+			return fmt.Errorf("--columns is only supported with -o csv or -o tsv")
+		}
+		if err := reporter.ValidateCSVColumns(o.Columns); err != nil {
+			return err
+		}
+	}
+
+	// Validate grouping
+	switch o.GroupBy {
+	case "", types.GroupByNamespace, types.GroupByWorkload, types.GroupByRegistry, types.GroupByRepository, types.GroupByContainerType:
+		// valid
+	default:
+		return fmt.Errorf("invalid --group-by value %q: must be \"namespace\", \"workload\", \"registry\", \"repository\" or \"container-type\"", o.GroupBy)
+	}
+
+	// Validate container types
+	for _, containerType := range o.ContainerTypes {
+		if !slices.Contains(types.ContainerTypes, containerType) {
+			return fmt.Errorf("invalid --container-type value %q: must be one of %s", containerType, strings.Join(types.ContainerTypes, ", "))
+		}
+	}
+
+	// Validate sort order
+	switch o.SortBy {
+	case "", types.SortBySize, types.SortByClusterSize:
+		// valid
+	default:
+		return fmt.Errorf("invalid --sort-by value %q: must be \"size\" or \"cluster-size\"", o.SortBy)
 	}
 
 	// Validate top images count
@@ -75,6 +215,30 @@ func (o *AnalyzeOptions) Validate() error {
 		return fmt.Errorf("--top-images must be at least 1, got %d", o.TopImages)
 	}
 
+	if err := validateNamespaceSelector(o.NamespaceSelector); err != nil {
+		return err
+	}
+	if err := validatePodFilters(o.FieldSelector, o.NodeSelector, o.PodPhases); err != nil {
+		return err
+	}
+
+	if o.PodPageSize < 0 || o.NodePageSize < 0 {
+		return fmt.Errorf("--pod-page-size and --node-page-size must not be negative; use --unpaginated to list everything in one request")
+	}
+
+	// Watch mode re-renders the table or streams JSON lines, and needs a live cluster
+	if o.Watch {
+		if o.OutputFormat != "table" && o.OutputFormat != "json" {
+			return fmt.Errorf("--watch only supports -o table and -o json, got %q", o.OutputFormat)
+		}
+		if len(o.FromFiles) > 0 {
+			return fmt.Errorf("--watch cannot be used with --from-file")
+		}
+		if o.WatchInterval < 0 {
+			return fmt.Errorf("--watch-interval must not be negative, got %s", o.WatchInterval)
+		}
+	}
+
 	return nil
 }
 
@@ -83,37 +247,225 @@ func (o *AnalyzeOptions) Validate() error {
 func (o *AnalyzeOptions) Run(ctx context.Context) error {
 	// Create analysis configuration
 	config := types.DefaultAnalysisConfig()
+	config.GroupBy = o.GroupBy
+	config.PodPageSize = o.PodPageSize
+	config.NodePageSize = o.NodePageSize
+	config.TagHygiene = o.TagHygiene
+	config.TagDrift = o.TagDrift
+	config.ContainerTypes = o.ContainerTypes
+	config.PodFilter = types.PodFilter{FieldSelector: o.FieldSelector, Phases: o.PodPhases}
+	config.NodeFilter = types.NodeFilter{Names: o.Nodes, Selector: o.NodeSelector}
+	if o.policy != nil && o.policy.RequiresNamespaces() {
+		config.NamespaceUsage = true
+	}
+	// Tags are checked on the image references of the listed pods
+	if o.policy != nil && o.policy.DisallowLatestTag {
+		config.ListPods = true
+	}
+	for _, column := range o.Columns {
+		if column == reporter.ColumnNamespaces || column == reporter.ColumnContainerTypes {
+			config.ListPods = true
+		}
+	}
+	// The HTML image table can be filtered by namespace, and the report charts
+	// the size per namespace
+	if o.OutputFormat == "html" {
+		config.ListPods = true
+		config.NamespaceUsage = true
+	}
 
-	// Create cluster client with injected kubernetes interface
-	clusterClient := cluster.NewClient(o.KubernetesClient)
-
-	// Create analyzer with injected cluster client
-	podAnalyzer := analyzer.NewPodAnalyzer(clusterClient, config)
-
-	// Display analysis parameters
-	namespaceDisplay := o.Namespace
-	if namespaceDisplay == "" {
-		namespaceDisplay = "All"
+	// Display analysis parameters. Machine-readable output keeps stdout to the
+	// report alone so it can be saved and diffed.
+	header := o.Out
+	if o.OutputFormat != "table" {
+		header = o.ErrOut
 	}
-	fmt.Fprintf(o.Out, "Analyzing images in namespace: %s\n", namespaceDisplay)
+	fmt.Fprintf(header, "Analyzing images in namespace: %s\n", o.namespaceFilter())
 	if o.LabelSelector != "" {
-		fmt.Fprintf(o.Out, "Using label selector: %s\n", o.LabelSelector)
+		fmt.Fprintf(header, "Using label selector: %s\n", o.LabelSelector)
+	}
+	if o.FieldSelector != "" {
+		fmt.Fprintf(header, "Using field selector: %s\n", o.FieldSelector)
+	}
+	if len(o.PodPhases) > 0 {
+		fmt.Fprintf(header, "Pod phases: %s\n", strings.Join(o.PodPhases, ", "))
+	}
+	if !config.NodeFilter.IsAll() {
+		fmt.Fprintf(header, "Nodes: %s\n", config.NodeFilter)
+	}
+	if len(o.ContainerTypes) > 0 {
+		fmt.Fprintf(header, "Container types: %s\n", strings.Join(o.ContainerTypes, ", "))
+	}
+	if o.GroupBy != "" {
+		fmt.Fprintf(header, "Grouping by: %s\n", o.GroupBy)
+	}
+	fmt.Fprintln(header)
+
+	if o.Watch {
+		return o.runWatch(ctx, config)
 	}
-	fmt.Fprintln(o.Out)
 
 	// Run analysis
-	analysis, err := podAnalyzer.AnalyzePods(ctx, o.Namespace, o.LabelSelector)
+	analysis, err := o.analyze(ctx, o.KubernetesClient, o.namespaceFilter(), config)
 	if err != nil {
-		return fmt.Errorf("failed to analyze pods: %w", err)
+		return err
 	}
 
 	// Generate report
+	if err := o.newReporter().GenerateReportTo(o.Out, analysis); err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	if len(analysis.Violations) > 0 {
+		return &PolicyViolationError{Violations: analysis.Violations}
+	}
+
+	return nil
+}
+
+// runWatch keeps the pods and nodes up to date with informers and writes an
+// update whenever a change alters the analysis, until the context is cancelled.
+// Changes are rendered at most once per watch interval.
+func (o *AnalyzeOptions) runWatch(ctx context.Context, config *types.AnalysisConfig) error {
+	// Namespace selectors are resolved once; namespaces labelled later are not watched
+	filter, err := cluster.NewClient(o.KubernetesClient).ResolveNamespaces(ctx, o.namespaceFilter())
+	if err != nil {
+		return fmt.Errorf("failed to resolve namespaces: %w", err)
+	}
+
+	watcher := cluster.NewWatcher(o.KubernetesClient, filter, o.LabelSelector, config.GroupBy == types.GroupByWorkload)
+	if err := watcher.Start(ctx); err != nil {
+		return fmt.Errorf("failed to watch cluster: %w", err)
+	}
+	fmt.Fprintln(o.ErrOut, "Watching pods and nodes for changes; press Ctrl+C to stop")
+
+	rep := o.newReporter()
+	var previous *types.ImageAnalysis
+	for {
+		// Drop pending change signals; the snapshot below includes those changes
+		select {
+		case <-watcher.Changed():
+		default:
+		}
+
+		analysis, err := o.analyze(ctx, kubernetes.NewSnapshotClient(watcher.Snapshot()), filter, config)
+		if err != nil {
+			return err
+		}
+
+		var diff *types.ImageDiff
+		if previous != nil {
+			diff = types.DiffAnalyses(previous, analysis)
+		}
+		if diff == nil || diff.HasChanges() {
+			if err := rep.GenerateWatchUpdateTo(o.Out, analysis, diff, time.Now()); err != nil {
+				return fmt.Errorf("failed to generate report: %w", err)
+			}
+			previous = analysis
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-watcher.Changed():
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(o.WatchInterval):
+		}
+	}
+}
+
+// analyze runs the image analysis of the pods in the selected namespaces against
+// the given client and checks the policy
+func (o *AnalyzeOptions) analyze(ctx context.Context, k8sClient kubernetes.Interface, filter types.NamespaceFilter, config *types.AnalysisConfig) (*types.ImageAnalysis, error) {
+	clusterClient := cluster.NewClient(k8sClient)
+	podAnalyzer := analyzer.NewPodAnalyzer(clusterClient, config)
+	analysis, err := podAnalyzer.AnalyzePodsIn(ctx, filter, o.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze pods: %w", err)
+	}
+
+	// Check the policy, if any rules are set
+	if o.policy != nil && !o.policy.IsEmpty() {
+		analysis.Violations = o.policy.Evaluate(analysis)
+	}
+
+	return analysis, nil
+}
+
+// namespaceFilter returns the namespaces selected by the namespace flags
+func (o *AnalyzeOptions) namespaceFilter() types.NamespaceFilter {
+	return types.NamespaceFilter{
+		Include:  o.Namespaces,
+		Exclude:  o.ExcludeNamespaces,
+		Selector: o.NamespaceSelector,
+	}
+}
+
+// newReporter creates a reporter for the configured output options
+func (o *AnalyzeOptions) newReporter() *reporter.Reporter {
 	rep := reporter.NewReporter(o.OutputFormat)
 	rep.SetNoColor(o.NoColor)
 	rep.SetTopImages(o.TopImages)
-	if err := rep.GenerateReportTo(o.Out, analysis); err != nil {
-		return fmt.Errorf("failed to generate report: %w", err)
+	rep.SetSortBy(o.SortBy)
+	rep.SetColumns(o.Columns)
+	return rep
+}
+
+// newKubernetesClient creates a client that reads from the given snapshot files, or
+// from the cluster selected by kubeContext when no files are given
+func newKubernetesClient(kubeContext string, fromFiles []string) (kubernetes.Interface, error) {
+	if len(fromFiles) > 0 {
+		return kubernetes.NewFileClient(fromFiles...)
+	}
+	return kubernetes.NewClient(kubeContext)
+}
+
+// validatePodFilters checks the field selector, node selector and pod phases
+func validatePodFilters(fieldSelector, nodeSelector string, phases []string) error {
+	if fieldSelector != "" {
+		if _, err := fields.ParseSelector(fieldSelector); err != nil {
+			return fmt.Errorf("invalid --field-selector: %w", err)
+		}
+	}
+	if nodeSelector != "" {
+		if _, err := labels.Parse(nodeSelector); err != nil {
+			return fmt.Errorf("invalid --node-selector: %w", err)
+		}
+	}
+	for _, phase := range phases {
+		if !slices.Contains(types.PodPhases, phase) {
+			return fmt.Errorf("invalid --pod-phase value %q: must be one of %s", phase, strings.Join(types.PodPhases, ", "))
+		}
+	}
+	return nil
+}
+
+// foldNamespace adds the deprecated single namespace, if set, to the namespace list
+func foldNamespace(namespaces []string, namespace string) []string {
+	if namespace == "" || slices.Contains(namespaces, namespace) {
+		return namespaces
+	}
+	return append(namespaces, namespace)
+}
+
+// validateNamespaceSelector checks that the namespace selector, if any, parses
+func validateNamespaceSelector(selector string) error {
+	if selector == "" {
+		return nil
 	}
+	if _, err := labels.Parse(selector); err != nil {
+		return fmt.Errorf("invalid --namespace-selector: %w", err)
+	}
+	return nil
+}
 
+// validateSource checks that the cluster and snapshot file sources are not combined
+func validateSource(kubeContext string, fromFiles []string) error {
+	if kubeContext != "" && len(fromFiles) > 0 {
+		return fmt.Errorf("--context and --from-file cannot be used together")
+	}
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
 )
 
 // testPod creates a test pod with the given name, namespace, and container images.
@@ -55,6 +56,9 @@ func TestAnalyzeOptions_Complete(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "table", o.OutputFormat)
 		assert.Equal(t, 25, o.TopImages)
+		assert.Equal(t, "size", o.SortBy)
+		assert.Equal(t, int64(types.DefaultPodPageSize), o.PodPageSize)
+		assert.Equal(t, int64(types.DefaultNodePageSize), o.NodePageSize)
 		assert.NotNil(t, o.Out)
 		assert.NotNil(t, o.ErrOut)
 	})
@@ -74,6 +78,31 @@ func TestAnalyzeOptions_Complete(t *testing.T) {
 		assert.Equal(t, buf, o.Out) // same buffer instance
 	})
 
+	t.Run("unpaginated lists opt out of page sizes", func(t *testing.T) {
+		o := &AnalyzeOptions{
+			PodPageSize:      1000,
+			Unpaginated:      true,
+			KubernetesClient: kubernetes.NewFakeClient(),
+		}
+		require.NoError(t, o.Complete())
+		assert.Equal(t, int64(0), o.PodPageSize)
+		assert.Equal(t, int64(0), o.NodePageSize)
+	})
+
+	t.Run("deprecated namespace is folded into namespaces", func(t *testing.T) {
+		o := &AnalyzeOptions{
+			Namespace:        "payments",
+			Namespaces:       []string{"search"},
+			KubernetesClient: kubernetes.NewFakeClient(),
+		}
+		require.NoError(t, o.Complete())
+		assert.Equal(t, []string{"search", "payments"}, o.Namespaces)
+
+		// Completing again does not add it twice
+		require.NoError(t, o.Complete())
+		assert.Equal(t, []string{"search", "payments"}, o.Namespaces)
+	})
+
 	t.Run("skips kubernetes client when pre-injected", func(t *testing.T) {
 		fakeClient := kubernetes.NewFakeClient()
 		o := &AnalyzeOptions{
@@ -95,10 +124,41 @@ func TestAnalyzeOptions_Validate(t *testing.T) {
 	}{
 		{name: "valid table format", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25}},
 		{name: "valid json format", opts: AnalyzeOptions{OutputFormat: "json", TopImages: 10}},
-		{name: "invalid output format", opts: AnalyzeOptions{OutputFormat: "yaml", TopImages: 25}, expectError: "invalid output format"},
+		{name: "valid yaml format", opts: AnalyzeOptions{OutputFormat: "yaml", TopImages: 10}},
+		{name: "valid html format", opts: AnalyzeOptions{OutputFormat: "html", TopImages: 10}},
+		{name: "valid markdown format", opts: AnalyzeOptions{OutputFormat: "markdown", TopImages: 10}},
+		{name: "invalid output format", opts: AnalyzeOptions{OutputFormat: "xml", TopImages: 25}, expectError: "invalid output format"},
 		{name: "topImages zero", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 0}, expectError: "must be at least 1"},
 		{name: "topImages negative", opts: AnalyzeOptions{OutputFormat: "table", TopImages: -5}, expectError: "must be at least 1"},
 		{name: "topImages one is valid", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 1}},
+		{name: "group by namespace", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, GroupBy: "namespace"}},
+		{name: "group by workload", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, GroupBy: "workload"}},
+		{name: "group by registry", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, GroupBy: "registry"}},
+		{name: "group by repository", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, GroupBy: "repository"}},
+		{name: "group by container type", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, GroupBy: "container-type"}},
+		{name: "container type filter", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, ContainerTypes: []string{"ephemeral", "init"}}},
+		{name: "pod filters", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, FieldSelector: "status.phase!=Succeeded", PodPhases: []string{"Running"}, NodeSelector: "pool=gpu"}},
+		{name: "invalid field selector", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, FieldSelector: "status.phase"}, expectError: "invalid --field-selector"},
+		{name: "invalid node selector", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, NodeSelector: "pool in ("}, expectError: "invalid --node-selector"},
+		{name: "invalid pod phase", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, PodPhases: []string{"running"}}, expectError: "invalid --pod-phase value"},
+		{name: "invalid container type", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, ContainerTypes: []string{"debug"}}, expectError: "invalid --container-type value"},
+		{name: "sort by cluster size", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, SortBy: "cluster-size"}},
+		{name: "invalid sort by", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, SortBy: "name"}, expectError: "invalid --sort-by value"},
+		{name: "invalid group by", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, GroupBy: "team"}, expectError: "invalid --group-by value"},
+		{name: "valid csv format", opts: AnalyzeOptions{OutputFormat: "csv", TopImages: 25}},
+		{name: "valid go-template format", opts: AnalyzeOptions{OutputFormat: "go-template={{.kind}}", TopImages: 25}},
+		{name: "valid jsonpath format", opts: AnalyzeOptions{OutputFormat: "jsonpath={.summary}", TopImages: 25}},
+		{name: "invalid jsonpath expression", opts: AnalyzeOptions{OutputFormat: "jsonpath={.images[}", TopImages: 25}, expectError: "failed to parse jsonpath expression"},
+		{name: "tsv with columns", opts: AnalyzeOptions{OutputFormat: "tsv", TopImages: 25, Columns: []string{"name", "size"}}},
+		{name: "watch with json", opts: AnalyzeOptions{OutputFormat: "json", TopImages: 25, Watch: true}},
+		{name: "watch with yaml", opts: AnalyzeOptions{OutputFormat: "yaml", TopImages: 25, Watch: true}, expectError: "--watch only supports -o table and -o json"},
+		{name: "watch with from-file", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, Watch: true, FromFiles: []string{"snapshot.json.gz"}}, expectError: "--watch cannot be used with --from-file"},
+		{name: "unpaginated lists", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, Unpaginated: true}},
+		{name: "negative page size", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, PodPageSize: -1}, expectError: "must not be negative"},
+		{name: "namespace selector", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, NamespaceSelector: "team in (payments, search)"}},
+		{name: "invalid namespace selector", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, NamespaceSelector: "team in ("}, expectError: "invalid --namespace-selector"},
+		{name: "columns without csv", opts: AnalyzeOptions{OutputFormat: "table", TopImages: 25, Columns: []string{"name"}}, expectError: "--columns is only supported"},
+		{name: "invalid column", opts: AnalyzeOptions{OutputFormat: "csv", TopImages: 25, Columns: []string{"owner"}}, expectError: "invalid column"},
 	}
 
 	for _, tc := range tests {
@@ -128,7 +188,7 @@ func TestAnalyzeOptions_Run_TableOutput(t *testing.T) {
 	errOut := &bytes.Buffer{}
 
 	o := &AnalyzeOptions{
-		Namespace:        "default",
+		Namespaces:       []string{"default"},
 		OutputFormat:     "table",
 		TopImages:        25,
 		ShowHistogram:    true,
@@ -157,7 +217,7 @@ func TestAnalyzeOptions_Run_JSONOutput(t *testing.T) {
 	out := &bytes.Buffer{}
 
 	o := &AnalyzeOptions{
-		Namespace:        "default",
+		Namespaces:       []string{"default"},
 		OutputFormat:     "json",
 		TopImages:        25,
 		KubernetesClient: kubernetes.NewFakeClient(pod1, node),
@@ -184,6 +244,91 @@ func TestAnalyzeOptions_Run_JSONOutput(t *testing.T) {
 	assert.Equal(t, float64(1), summary["totalImages"])
 }
 
+func TestAnalyzeOptions_Run_NamespaceFilters(t *testing.T) {
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	objects := []runtime.Object{
+		namespace("payments-api", map[string]string{"team": "payments"}),
+		namespace("payments-jobs", map[string]string{"team": "payments"}),
+		namespace("search", map[string]string{"team": "search"}),
+		namespace("kube-system", nil),
+		testPod("api", "payments-api", "payments-api:v1"),
+		testPod("worker", "payments-jobs", "payments-worker:v1"),
+		testPod("search", "search", "search:v1"),
+		testPod("dns", "kube-system", "coredns:1.9"),
+		testNode("node1", map[string]int64{
+			"payments-api:v1":    100000000,
+			"payments-worker:v1": 200000000,
+			"search:v1":          300000000,
+			"coredns:1.9":        50000000,
+		}),
+	}
+
+	tests := []struct {
+		name         string
+		opts         AnalyzeOptions
+		expectHeader string
+		expectImages []string
+		expectError  string
+	}{
+		{
+			name:         "several namespaces",
+			opts:         AnalyzeOptions{Namespaces: []string{"payments-api", "search"}},
+			expectHeader: "namespace: payments-api, search",
+			expectImages: []string{"payments-api:v1", "search:v1"},
+		},
+		{
+			name:         "excluded namespace",
+			opts:         AnalyzeOptions{ExcludeNamespaces: []string{"kube-system"}},
+			expectHeader: "namespace: All (excluding kube-system)",
+			expectImages: []string{"payments-api:v1", "payments-worker:v1", "search:v1"},
+		},
+		{
+			name:         "namespace selector",
+			opts:         AnalyzeOptions{NamespaceSelector: "team=payments"},
+			expectHeader: "namespace: All matching team=payments",
+			expectImages: []string{"payments-api:v1", "payments-worker:v1"},
+		},
+		{
+			name:         "namespace selector with exclusion",
+			opts:         AnalyzeOptions{NamespaceSelector: "team=payments", ExcludeNamespaces: []string{"payments-jobs"}},
+			expectImages: []string{"payments-api:v1"},
+		},
+		{
+			name:        "namespace selector matching nothing",
+			opts:        AnalyzeOptions{NamespaceSelector: "team=billing"},
+			expectError: "no namespaces match the namespace selector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			o := tt.opts
+			o.OutputFormat = "csv"
+			o.Columns = []string{"name"}
+			o.TopImages = 25
+			o.KubernetesClient = kubernetes.NewFakeClient(objects...)
+			o.Out = out
+			errOut := &bytes.Buffer{}
+			o.ErrOut = errOut
+
+			err := o.Run(context.Background())
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Contains(t, errOut.String(), tt.expectHeader)
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			assert.ElementsMatch(t, tt.expectImages, lines[1:])
+		})
+	}
+}
+
 func TestAnalyzeOptions_Run_AllNamespaces(t *testing.T) {
 	// No pods needed for all-namespaces mode -- analyzer uses node images directly
 	node := testNode("node1", map[string]int64{
@@ -195,7 +340,7 @@ func TestAnalyzeOptions_Run_AllNamespaces(t *testing.T) {
 	out := &bytes.Buffer{}
 
 	o := &AnalyzeOptions{
-		Namespace:        "", // empty = all namespaces
+		Namespaces:       nil, // empty = all namespaces
 		OutputFormat:     "table",
 		TopImages:        25,
 		NoColor:          true,
@@ -232,7 +377,7 @@ func TestAnalyzeOptions_Run_WithLabelSelector(t *testing.T) {
 	objects := []runtime.Object{pod1, pod2, node}
 
 	o := &AnalyzeOptions{
-		Namespace:        "default",
+		Namespaces:       []string{"default"},
 		LabelSelector:    "app=web",
 		OutputFormat:     "table",
 		TopImages:        25,
@@ -250,3 +395,163 @@ func TestAnalyzeOptions_Run_WithLabelSelector(t *testing.T) {
 	// With label selector app=web, only nginx:1.21 should be analyzed
 	assert.Contains(t, output, "nginx:1.21")
 }
+
+func TestAnalyzeOptions_Run_TagHygiene(t *testing.T) {
+	pod1 := testPod("pod1", "team-a", "nginx")
+	pod2 := testPod("pod2", "team-b", "nginx:1.21")
+	node := testNode("node1", map[string]int64{
+		"nginx:latest": 100000000,
+		"nginx:1.21":   100000000,
+	})
+
+	out := &bytes.Buffer{}
+	o := &AnalyzeOptions{
+		OutputFormat:     "table",
+		NoColor:          true,
+		TopImages:        25,
+		TagHygiene:       true,
+		KubernetesClient: kubernetes.NewFakeClient(pod1, pod2, node),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+
+	err := o.Run(context.Background())
+	require.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "Tag Hygiene")
+	assert.Contains(t, output, "untagged, unpinned")
+	assert.Contains(t, output, "Version Sprawl")
+	assert.Contains(t, output, "docker.io/library/nginx")
+}
+
+func TestAnalyzeOptions_Run_NodeSelector(t *testing.T) {
+	gpuNode := testNode("gpu-1", map[string]int64{"cuda:12": 900000000})
+	gpuNode.Labels = map[string]string{"pool": "gpu"}
+	cpuNode := testNode("cpu-1", map[string]int64{"nginx:1.21": 100000000})
+
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	o := &AnalyzeOptions{
+		OutputFormat:     "csv",
+		TopImages:        25,
+		NodeSelector:     "pool=gpu",
+		Columns:          []string{"name"},
+		KubernetesClient: kubernetes.NewFakeClient(gpuNode, cpuNode),
+		Out:              out,
+		ErrOut:           errOut,
+	}
+
+	err := o.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Contains(t, errOut.String(), "Nodes: All matching pool=gpu")
+	assert.Equal(t, "name\ncuda:12\n", out.String())
+}
+
+func TestAnalyzeOptions_Run_GroupByNamespace(t *testing.T) {
+	pod1 := testPod("pod1", "team-a", "nginx:1.21")
+	pod2 := testPod("pod2", "team-b", "redis:6.2")
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"redis:6.2":  50000000,
+	})
+
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+
+	o := &AnalyzeOptions{
+		OutputFormat:     "json",
+		TopImages:        25,
+		GroupBy:          "namespace",
+		KubernetesClient: kubernetes.NewFakeClient(pod1, pod2, node),
+		Out:              out,
+		ErrOut:           errOut,
+	}
+
+	err := o.Run(context.Background())
+	require.NoError(t, err)
+
+	// JSON output keeps the analysis parameters off stdout
+	assert.Contains(t, errOut.String(), "Grouping by: namespace")
+
+	var result map[string]interface{}
+	err = json.Unmarshal(out.Bytes(), &result)
+	require.NoError(t, err, "failed to parse JSON output")
+
+	namespaces, ok := result["namespaces"].([]interface{})
+	require.True(t, ok, "expected namespaces array in JSON")
+	require.Len(t, namespaces, 2)
+	first := namespaces[0].(map[string]interface{})
+	assert.Equal(t, "team-a", first["namespace"])
+	assert.Equal(t, float64(100000000), first["uniqueBytes"])
+}
+
+func TestAnalyzeOptions_Run_CSVNamespacesColumn(t *testing.T) {
+	pod1 := testPod("pod1", "team-a", "nginx:1.21")
+	pod2 := testPod("pod2", "team-b", "nginx:1.21")
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"redis:6.2":  50000000,
+	})
+
+	out := &bytes.Buffer{}
+
+	o := &AnalyzeOptions{
+		OutputFormat:     "csv",
+		Columns:          []string{"name", "size", "namespaces"},
+		KubernetesClient: kubernetes.NewFakeClient(pod1, pod2, node),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+	require.NoError(t, o.Run(context.Background()))
+
+	// The namespaces column lists pods, so only images used by pods are reported
+	assert.Equal(t, "name,size,namespaces\nnginx:1.21,100000000,team-a;team-b\n", out.String())
+}
+
+func TestAnalyzeOptions_Run_HTMLNamespaceChart(t *testing.T) {
+	pod1 := testPod("pod1", "team-a", "nginx:1.21")
+	pod2 := testPod("pod2", "team-b", "redis:6.2")
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"redis:6.2":  50000000,
+	})
+
+	out := &bytes.Buffer{}
+
+	o := &AnalyzeOptions{
+		OutputFormat:     "html",
+		KubernetesClient: kubernetes.NewFakeClient(pod1, pod2, node),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+	require.NoError(t, o.Run(context.Background()))
+
+	// The namespace chart is shown without --group-by namespace
+	assert.Contains(t, out.String(), "Size by Namespace")
+	assert.Contains(t, out.String(), "team-b")
+}
+
+func TestAnalyzeOptions_Run_JSONPathOutput(t *testing.T) {
+	node := testNode("node1", map[string]int64{"nginx:1.21": 100000000})
+
+	out := &bytes.Buffer{}
+
+	o := &AnalyzeOptions{
+		OutputFormat:     "jsonpath={.images[0].name}",
+		KubernetesClient: kubernetes.NewFakeClient(node),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+	require.NoError(t, o.Run(context.Background()))
+
+	// Only the template output goes to stdout
+	assert.Equal(t, "nginx:1.21", out.String())
+}
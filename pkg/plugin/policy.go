@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// ExitCodePolicyViolation is the process exit code when the analysis violates the
+// policy, distinct from the exit code 1 used for all other errors
+const ExitCodePolicyViolation = 3
+
+// PolicyViolationError is returned by Run when the analysis violates the policy.
+// The report, including the violations, has already been written.
+type PolicyViolationError struct {
+	Violations []types.PolicyViolation
+}
+
+// Error implements the error interface
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("found %d policy violations", len(e.Violations))
+}
+
+// loadPolicy reads a YAML or JSON policy file, rejecting unknown fields
+func loadPolicy(path string) (*types.Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	policy := &types.Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// parseQuantity parses a size flag such as "2Gi"
+func parseQuantity(flag, value string) (*resource.Quantity, error) {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q: %w", flag, value, err)
+	}
+	return &q, nil
+}
@@ -0,0 +1,167 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+func TestAnalyzeOptions_Complete_Policy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`maxImageSize: 1Gi
+disallowLatestTag: true
+allowedRegistries:
+- docker.io
+namespaceBudgets:
+  team-a: 10Gi
+`), 0o600))
+
+	t.Run("policy file", func(t *testing.T) {
+		o := &AnalyzeOptions{PolicyFile: path, KubernetesClient: kubernetes.NewFakeClient()}
+		require.NoError(t, o.Complete())
+		assert.Equal(t, "1Gi", o.policy.MaxImageSize.String())
+		assert.True(t, o.policy.DisallowLatestTag)
+		assert.Equal(t, []string{"docker.io"}, o.policy.AllowedRegistries)
+		assert.True(t, o.policy.RequiresNamespaces())
+	})
+
+	t.Run("flags override policy file", func(t *testing.T) {
+		o := &AnalyzeOptions{
+			PolicyFile:        path,
+			MaxImageSize:      "500Mi",
+			AllowedRegistries: []string{"gcr.io"},
+			KubernetesClient:  kubernetes.NewFakeClient(),
+		}
+		require.NoError(t, o.Complete())
+		assert.Equal(t, "500Mi", o.policy.MaxImageSize.String())
+		assert.Equal(t, []string{"gcr.io"}, o.policy.AllowedRegistries)
+	})
+
+	t.Run("invalid size flag", func(t *testing.T) {
+		o := &AnalyzeOptions{MaxImageSize: "huge", KubernetesClient: kubernetes.NewFakeClient()}
+		err := o.Complete()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --max-image-size value")
+	})
+
+	t.Run("unknown policy field", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.yaml")
+		require.NoError(t, os.WriteFile(bad, []byte("maxSize: 1Gi\n"), 0o600))
+		o := &AnalyzeOptions{PolicyFile: bad, KubernetesClient: kubernetes.NewFakeClient()}
+		err := o.Complete()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse policy file")
+	})
+}
+
+func TestAnalyzeOptions_Run_NamespaceBudgetWithWorkloads(t *testing.T) {
+	pod := testPod("pod1", "team-a", "nginx:1.21")
+	node := testNode("node1", map[string]int64{"nginx:1.21": 100000000})
+
+	out := &bytes.Buffer{}
+	o := &AnalyzeOptions{
+		OutputFormat:     "json",
+		NamespaceBudget:  "60M",
+		GroupBy:          "workload",
+		KubernetesClient: kubernetes.NewFakeClient(pod, node),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+
+	// Budgets are checked without replacing the requested grouping
+	err := o.Run(context.Background())
+	var violationErr *PolicyViolationError
+	require.True(t, errors.As(err, &violationErr), "expected a policy violation error, got %v", err)
+	require.Len(t, violationErr.Violations, 1)
+	assert.Equal(t, types.RuleNamespaceBudget, violationErr.Violations[0].Rule)
+
+	var report types.ImageReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.NotEmpty(t, report.Workloads)
+	assert.NotEmpty(t, report.Namespaces)
+}
+
+func TestAnalyzeOptions_Run_LatestTagUsesPodReference(t *testing.T) {
+	// The pod pins the image by digest, but the node also lists it as :latest
+	digest := "sha256:0123456789012345678901234567890123456789012345678901234567890123"
+	pod := testPod("pod1", "team-a", "registry.example.com/app@"+digest)
+	node := testNode("node1", nil)
+	node.Status.Images = []corev1.ContainerImage{{
+		Names:     []string{"registry.example.com/app@" + digest, "registry.example.com/app:latest"},
+		SizeBytes: 100000000,
+	}}
+
+	o := &AnalyzeOptions{
+		OutputFormat:      "json",
+		DisallowLatestTag: true,
+		KubernetesClient:  kubernetes.NewFakeClient(pod, node),
+		Out:               &bytes.Buffer{},
+		ErrOut:            &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+	assert.NoError(t, o.Run(context.Background()))
+}
+
+func TestAnalyzeOptions_Run_PolicyViolations(t *testing.T) {
+	pod1 := testPod("pod1", "team-a", "nginx:latest")
+	pod2 := testPod("pod2", "team-b", "redis:6.2")
+	node := testNode("node1", map[string]int64{
+		"nginx:latest": 100000000,
+		"redis:6.2":    50000000,
+	})
+
+	out := &bytes.Buffer{}
+	o := &AnalyzeOptions{
+		OutputFormat:      "json",
+		NamespaceBudget:   "60M",
+		DisallowLatestTag: true,
+		KubernetesClient:  kubernetes.NewFakeClient(pod1, pod2, node),
+		Out:               out,
+		ErrOut:            &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+
+	err := o.Run(context.Background())
+	var violationErr *PolicyViolationError
+	require.True(t, errors.As(err, &violationErr), "expected a policy violation error, got %v", err)
+	assert.Len(t, violationErr.Violations, 2)
+
+	// The report, including violations, is written before the error is returned
+	var report types.ImageReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Len(t, report.Violations, 2)
+	assert.Equal(t, types.RuleLatestTag, report.Violations[0].Rule)
+	assert.Equal(t, types.RuleNamespaceBudget, report.Violations[1].Rule)
+	assert.Equal(t, "team-a", report.Violations[1].Subject)
+}
+
+func TestAnalyzeOptions_Run_PolicyPasses(t *testing.T) {
+	out := &bytes.Buffer{}
+	o := &AnalyzeOptions{
+		NoColor:      true,
+		MaxImageSize: "1Gi",
+		KubernetesClient: kubernetes.NewFakeClient(testNode("node1", map[string]int64{
+			"redis:6.2": 50000000,
+		})),
+		Out:    out,
+		ErrOut: &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Run(context.Background()))
+	assert.NotContains(t, out.String(), "Policy Violations")
+}
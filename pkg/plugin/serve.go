@@ -0,0 +1,181 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/analyzer"
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/exporter"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// shutdownTimeout bounds how long in-flight scrapes may take once the server stops
+const shutdownTimeout = 5 * time.Second
+
+// ServeOptions holds all the configuration and dependencies for running the
+// analysis on an interval and exporting the results as Prometheus metrics.
+// It follows the kubectl plugin Complete/Validate/Run pattern.
+type ServeOptions struct {
+	// CLI flags
+	Namespaces        []string
+	ExcludeNamespaces []string
+	NamespaceSelector string
+	LabelSelector     string
+	KubeContext       string
+	FromFiles         []string
+	ListenAddress     string
+	Interval          time.Duration
+
+	// Namespace is the single target namespace.
+	//
+	// Deprecated: use Namespaces. Complete adds it to Namespaces.
+	Namespace string
+
+	// Injected dependencies
+	KubernetesClient kubernetes.Interface
+	Out              io.Writer
+	ErrOut           io.Writer
+}
+
+// Complete populates defaults for unset fields and creates the kubernetes client
+// if one has not been injected. Tests can pre-inject a FakeClient to skip creation.
+func (o *ServeOptions) Complete() error {
+	// Set defaults for unset fields
+	if o.ListenAddress == "" {
+		o.ListenAddress = ":8080"
+	}
+	if o.Interval == 0 {
+		o.Interval = 5 * time.Minute
+	}
+	o.Namespaces = foldNamespace(o.Namespaces, o.Namespace)
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	if o.ErrOut == nil {
+		o.ErrOut = os.Stderr
+	}
+
+	// Create kubernetes client if not injected (production path)
+	if o.KubernetesClient == nil {
+		k8sClient, err := newKubernetesClient(o.KubeContext, o.FromFiles)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+		o.KubernetesClient = k8sClient
+	}
+
+	return nil
+}
+
+// Validate checks that all options have valid values.
+func (o *ServeOptions) Validate() error {
+	if err := validateSource(o.KubeContext, o.FromFiles); err != nil {
+		return err
+	}
+
+	if err := validateNamespaceSelector(o.NamespaceSelector); err != nil {
+		return err
+	}
+
+	if o.Interval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s, got %s", o.Interval)
+	}
+
+	return nil
+}
+
+// Run serves /metrics and /healthz on the listen address and analyzes the cluster
+// every interval until the context is cancelled. Failed analyses are logged and
+// counted; the metrics of the last successful analysis keep being served.
+func (o *ServeOptions) Run(ctx context.Context) error {
+	exp := exporter.NewExporter()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if !exp.Ready() {
+			http.Error(w, "no successful analysis yet", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	listener, err := net.Listen("tcp", o.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", o.ListenAddress, err)
+	}
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	fmt.Fprintf(o.ErrOut, "Serving metrics at http://%s/metrics, analyzing every %s\n", listener.Addr(), o.Interval)
+
+	// One client serves every analysis. Its progress output is discarded so that
+	// each interval doesn't redraw the spinner in the logs.
+	clusterClient := cluster.NewClient(o.KubernetesClient)
+	clusterClient.SetProgressOutput(io.Discard)
+
+	ticker := time.NewTicker(o.Interval)
+	defer ticker.Stop()
+	for {
+		if err := o.collect(ctx, clusterClient, exp); err != nil {
+			exp.RecordFailure()
+			fmt.Fprintf(o.ErrOut, "Analysis failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				return fmt.Errorf("failed to shut down metrics server: %w", err)
+			}
+			return nil
+		case err := <-serveErr:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to serve metrics: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect runs the image and node analyses and updates the exported metrics
+func (o *ServeOptions) collect(ctx context.Context, clusterClient *cluster.Client, exp *exporter.Exporter) error {
+	// Attribute image sizes to namespaces for the per-namespace metrics
+	config := types.DefaultAnalysisConfig()
+	config.GroupBy = types.GroupByNamespace
+	// The namespace selector is resolved on every analysis, so newly labelled
+	// namespaces are picked up
+	filter := types.NamespaceFilter{
+		Include:  o.Namespaces,
+		Exclude:  o.ExcludeNamespaces,
+		Selector: o.NamespaceSelector,
+	}
+	images, err := analyzer.NewPodAnalyzer(clusterClient, config).AnalyzePodsIn(ctx, filter, o.LabelSelector)
+	if err != nil {
+		return fmt.Errorf("failed to analyze pods: %w", err)
+	}
+
+	// Node usage lists every pod whatever the selection, so images used by pods
+	// outside it are not reported as unreferenced
+	nodes, err := analyzer.NewNodeAnalyzer(clusterClient).AnalyzeNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to analyze nodes: %w", err)
+	}
+
+	if err := exp.Update(images, nodes, time.Now()); err != nil {
+		return fmt.Errorf("failed to update metrics: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/cluster"
+	"github.com/ronaknnathani/kubectl-analyze-images/internal/exporter"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+)
+
+func TestServeOptions_Complete(t *testing.T) {
+	o := &ServeOptions{KubernetesClient: kubernetes.NewFakeClient()}
+	require.NoError(t, o.Complete())
+	assert.Equal(t, ":8080", o.ListenAddress)
+	assert.Equal(t, 5*time.Minute, o.Interval)
+	assert.NotNil(t, o.Out)
+	assert.NotNil(t, o.ErrOut)
+}
+
+func TestServeOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        ServeOptions
+		expectError string
+	}{
+		{name: "valid", opts: ServeOptions{Interval: time.Minute}},
+		{name: "interval too short", opts: ServeOptions{Interval: time.Millisecond}, expectError: "--interval must be at least 1s"},
+		{
+			name:        "context with from-file",
+			opts:        ServeOptions{Interval: time.Minute, KubeContext: "prod", FromFiles: []string{"snapshot.json.gz"}},
+			expectError: "--context and --from-file cannot be used together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestServeOptions_Collect(t *testing.T) {
+	pod := testPod("pod1", "default", "nginx:1.21")
+	pod.Spec.NodeName = "node1"
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"redis:6.2":  50000000,
+	})
+
+	o := &ServeOptions{
+		KubernetesClient: kubernetes.NewFakeClient(pod, node),
+		Out:              &bytes.Buffer{},
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+
+	progress := &bytes.Buffer{}
+	clusterClient := cluster.NewClient(o.KubernetesClient)
+	clusterClient.SetProgressOutput(progress)
+
+	exp := exporter.NewExporter()
+	require.NoError(t, o.collect(context.Background(), clusterClient, exp))
+	assert.True(t, exp.Ready())
+
+	// Progress goes to the client's output
+	assert.Contains(t, progress.String(), "Completed analyzing 1 images")
+
+	rec := httptest.NewRecorder()
+	exp.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	output := rec.Body.String()
+	assert.Contains(t, output, `analyze_images_image_size_bytes{image="nginx:1.21"`)
+	assert.Contains(t, output, `analyze_images_namespace_image_bytes{namespace="default"} 1e+08`)
+	assert.Contains(t, output, `analyze_images_node_image_bytes{node="node1"} 1.5e+08`)
+	assert.Contains(t, output, `analyze_images_node_unreferenced_image_bytes{node="node1"} 5e+07`)
+	assert.Contains(t, output, "analyze_images_inaccessible_images 0\n")
+}
+
+func TestServeOptions_Collect_NamespaceSelection(t *testing.T) {
+	web := testPod("web", "team-a", "nginx:1.21")
+	web.Spec.NodeName = "node1"
+	cache := testPod("cache", "team-b", "redis:6.2")
+	cache.Spec.NodeName = "node1"
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"redis:6.2":  50000000,
+	})
+
+	o := &ServeOptions{
+		Namespaces:       []string{"team-a"},
+		KubernetesClient: kubernetes.NewFakeClient(web, cache, node),
+		Out:              &bytes.Buffer{},
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+
+	clusterClient := cluster.NewClient(o.KubernetesClient)
+	clusterClient.SetProgressOutput(&bytes.Buffer{})
+
+	exp := exporter.NewExporter()
+	require.NoError(t, o.collect(context.Background(), clusterClient, exp))
+
+	rec := httptest.NewRecorder()
+	exp.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	output := rec.Body.String()
+	assert.Contains(t, output, `analyze_images_namespace_image_bytes{namespace="team-a"} 1e+08`)
+	assert.NotContains(t, output, `namespace="team-b"`)
+	// Images of pods outside the selection are still referenced
+	assert.Contains(t, output, `analyze_images_node_unreferenced_image_bytes{node="node1"} 0`)
+}
+
+func TestServeOptions_Run_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errOut := &bytes.Buffer{}
+	o := &ServeOptions{
+		ListenAddress:    "127.0.0.1:0",
+		Interval:         time.Hour,
+		KubernetesClient: kubernetes.NewFakeClient(testNode("node1", map[string]int64{"nginx:1.21": 100})),
+		Out:              &bytes.Buffer{},
+		ErrOut:           errOut,
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Run(ctx))
+	assert.Contains(t, errOut.String(), "Serving metrics at http://127.0.0.1:")
+}
+
+func TestServeOptions_Run_ListenError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	o := &ServeOptions{
+		ListenAddress:    listener.Addr().String(),
+		Interval:         time.Hour,
+		KubernetesClient: kubernetes.NewFakeClient(),
+		Out:              &bytes.Buffer{},
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	err = o.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to listen on")
+}
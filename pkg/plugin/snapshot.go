@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+)
+
+// SnapshotOptions holds all the configuration and dependencies for exporting the
+// objects needed for offline analysis. It follows the kubectl plugin Complete/Validate/Run pattern.
+type SnapshotOptions struct {
+	// CLI flags
+	Namespaces  []string
+	OutputFile  string
+	KubeContext string
+
+	// Namespace is the single namespace to export.
+	//
+	// Deprecated: use Namespaces. Complete adds it to Namespaces.
+	Namespace string
+
+	// Injected dependencies
+	KubernetesClient kubernetes.Interface
+	Out              io.Writer
+	ErrOut           io.Writer
+}
+
+// Complete populates defaults for unset fields and creates the kubernetes client
+// if one has not been injected. Tests can pre-inject a FakeClient to skip creation.
+func (o *SnapshotOptions) Complete() error {
+	// Set defaults for unset fields
+	if o.OutputFile == "" {
+		o.OutputFile = "snapshot.json.gz"
+	}
+	o.Namespaces = foldNamespace(o.Namespaces, o.Namespace)
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	if o.ErrOut == nil {
+		o.ErrOut = os.Stderr
+	}
+
+	// Create kubernetes client if not injected (production path)
+	if o.KubernetesClient == nil {
+		k8sClient, err := kubernetes.NewClient(o.KubeContext)
+		if err != nil {
+			return fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+		o.KubernetesClient = k8sClient
+	}
+
+	return nil
+}
+
+// Validate checks that all options have valid values.
+func (o *SnapshotOptions) Validate() error {
+	if o.OutputFile == "" {
+		return fmt.Errorf("--file must not be empty")
+	}
+
+	return nil
+}
+
+// Run lists pods, nodes, replica sets and jobs and writes them to a compressed
+// snapshot file. An output file of "-" writes the snapshot to Out.
+func (o *SnapshotOptions) Run(ctx context.Context) error {
+	snapshot, err := kubernetes.TakeSnapshotIn(ctx, o.KubernetesClient, o.Namespaces)
+	if err != nil {
+		return fmt.Errorf("failed to take snapshot: %w", err)
+	}
+
+	if o.OutputFile == "-" {
+		return snapshot.Write(o.Out)
+	}
+
+	file, err := os.Create(o.OutputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	if err := snapshot.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot file: %w", err)
+	}
+
+	fmt.Fprintf(o.ErrOut, "✓ Wrote %d pods and %d nodes to %s\n", len(snapshot.Pods), len(snapshot.Nodes), o.OutputFile)
+	return nil
+}
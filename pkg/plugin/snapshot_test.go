@@ -0,0 +1,109 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+)
+
+func TestSnapshotOptions_Complete(t *testing.T) {
+	o := &SnapshotOptions{KubernetesClient: kubernetes.NewFakeClient()}
+	require.NoError(t, o.Complete())
+	assert.Equal(t, "snapshot.json.gz", o.OutputFile)
+	assert.NotNil(t, o.Out)
+	assert.NotNil(t, o.ErrOut)
+}
+
+func TestSnapshotOptions_Run_AnalyzeFromFile(t *testing.T) {
+	pod := testPod("pod1", "default", "nginx:1.21")
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"redis:6.2":  50000000,
+	})
+
+	path := filepath.Join(t.TempDir(), "cluster.json.gz")
+	errOut := &bytes.Buffer{}
+
+	so := &SnapshotOptions{
+		OutputFile:       path,
+		KubernetesClient: kubernetes.NewFakeClient(pod, node),
+		Out:              &bytes.Buffer{},
+		ErrOut:           errOut,
+	}
+	require.NoError(t, so.Complete())
+	require.NoError(t, so.Validate())
+	require.NoError(t, so.Run(context.Background()))
+	assert.Contains(t, errOut.String(), "Wrote 1 pods and 1 nodes")
+
+	// Analyze the snapshot without a cluster
+	out := &bytes.Buffer{}
+	o := &AnalyzeOptions{
+		Namespaces: []string{"default"},
+		FromFiles:  []string{path},
+		NoColor:    true,
+		Out:        out,
+		ErrOut:     &bytes.Buffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+	require.NoError(t, o.Run(context.Background()))
+
+	output := out.String()
+	assert.Contains(t, output, "nginx:1.21")
+	assert.NotContains(t, output, "redis:6.2")
+}
+
+func TestSnapshotOptions_Run_Stdout(t *testing.T) {
+	out := &bytes.Buffer{}
+	so := &SnapshotOptions{
+		OutputFile:       "-",
+		KubernetesClient: kubernetes.NewFakeClient(testNode("node1", nil)),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+	require.NoError(t, so.Run(context.Background()))
+
+	snap, err := kubernetes.ReadSnapshot(out)
+	require.NoError(t, err)
+	assert.Len(t, snap.Nodes, 1)
+}
+
+func TestSnapshotOptions_Run_Namespaces(t *testing.T) {
+	out := &bytes.Buffer{}
+	so := &SnapshotOptions{
+		Namespaces: []string{"team-a", "team-b"},
+		Namespace:  "team-c",
+		OutputFile: "-",
+		KubernetesClient: kubernetes.NewFakeClient(
+			testPod("web", "team-a", "nginx:1.21"),
+			testPod("api", "team-b", "api:v1"),
+			testPod("db", "team-c", "postgres:15"),
+			testPod("cache", "team-d", "redis:6.2"),
+		),
+		Out:    out,
+		ErrOut: &bytes.Buffer{},
+	}
+	require.NoError(t, so.Complete())
+	require.NoError(t, so.Run(context.Background()))
+
+	snap, err := kubernetes.ReadSnapshot(out)
+	require.NoError(t, err)
+	names := make([]string, 0, len(snap.Pods))
+	for _, pod := range snap.Pods {
+		names = append(names, pod.Name)
+	}
+	assert.ElementsMatch(t, []string{"web", "api", "db"}, names)
+}
+
+func TestFromFile_ConflictsWithContext(t *testing.T) {
+	o := AnalyzeOptions{OutputFormat: "table", TopImages: 25, KubeContext: "prod", FromFiles: []string{"snap.json.gz"}}
+	err := o.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be used together")
+}
@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+)
+
+func TestUnusedOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        UnusedOptions
+		expectError string
+	}{
+		{name: "valid table format", opts: UnusedOptions{OutputFormat: "table", TopImages: 25}},
+		{name: "valid json format", opts: UnusedOptions{OutputFormat: "json", TopImages: 25}},
+		{name: "invalid output format", opts: UnusedOptions{OutputFormat: "xml", TopImages: 25}, expectError: "invalid output format"},
+		{name: "topImages zero", opts: UnusedOptions{OutputFormat: "table", TopImages: 0}, expectError: "must be at least 1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if tc.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnusedOptions_Run(t *testing.T) {
+	pod := testPod("pod1", "default", "nginx:1.21")
+	pod.Spec.NodeName = "node1"
+	node := testNode("node1", map[string]int64{
+		"nginx:1.21": 100000000,
+		"old:v1":     50000000,
+	})
+
+	out := &bytes.Buffer{}
+
+	o := &UnusedOptions{
+		NoColor:          true,
+		KubernetesClient: kubernetes.NewFakeClient(pod, node),
+		Out:              out,
+		ErrOut:           &bytes.Buffer{},
+	}
+
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+	err := o.Run(context.Background())
+	require.NoError(t, err)
+
+	output := out.String()
+	assert.Contains(t, output, "Reclaimable Space by Node")
+	assert.Contains(t, output, "old:v1")
+	assert.NotContains(t, output, "nginx:1.21")
+}
@@ -0,0 +1,117 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/kubernetes"
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/types"
+)
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// lines returns the complete lines written so far
+func (b *syncBuffer) lines() []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(b.String()))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestAnalyzeOptions_Run_WatchJSONLines(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := kubernetes.NewFakeClient(
+		testPod("pod1", "default", "nginx:1.21"),
+		testNode("node1", map[string]int64{
+			"nginx:1.21": 100000000,
+			"redis:6.2":  50000000,
+		}),
+	)
+	clientset := client.(*kubernetes.FakeClient).Clientset()
+
+	out := &syncBuffer{}
+	o := &AnalyzeOptions{
+		Namespaces:       []string{"default"},
+		OutputFormat:     "json",
+		Watch:            true,
+		WatchInterval:    10 * time.Millisecond,
+		KubernetesClient: client,
+		Out:              out,
+		ErrOut:           &syncBuffer{},
+	}
+	require.NoError(t, o.Complete())
+	require.NoError(t, o.Validate())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- o.Run(ctx)
+	}()
+
+	// The first line is the full report
+	require.Eventually(t, func() bool { return len(out.lines()) >= 1 }, 5*time.Second, 10*time.Millisecond)
+	var report types.ImageReport
+	require.NoError(t, json.Unmarshal([]byte(out.lines()[0]), &report))
+	assert.Equal(t, types.ImageReportKind, report.Kind)
+	require.Len(t, report.Images, 1)
+	assert.Equal(t, "nginx:1.21", report.Images[0].Name)
+
+	// A pod using another image produces a diff line. The fake clientset only
+	// delivers events to watches that have started, so wait for the pod watch.
+	require.Eventually(t, func() bool {
+		for _, action := range clientset.Actions() {
+			if action.GetVerb() == "watch" && action.GetResource().Resource == "pods" {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err := clientset.CoreV1().Pods("default").Create(ctx, testPod("pod2", "default", "redis:6.2"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(out.lines()) >= 2 }, 5*time.Second, 10*time.Millisecond)
+
+	var diff types.ImageDiffReport
+	require.NoError(t, json.Unmarshal([]byte(out.lines()[1]), &diff))
+	assert.Equal(t, types.ImageDiffReportKind, diff.Kind)
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, "redis:6.2", diff.Added[0].Name)
+	assert.Empty(t, diff.Removed)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop after the context was cancelled")
+	}
+}
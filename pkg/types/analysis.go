@@ -4,27 +4,67 @@ import (
 	"time"
 )
 
+// Supported values for AnalysisConfig.GroupBy
+const (
+	GroupByNamespace     = "namespace"
+	GroupByWorkload      = "workload"
+	GroupByRegistry      = "registry"
+	GroupByRepository    = "repository"
+	GroupByContainerType = "container-type"
+)
+
+// Supported sort orders for the top images report
+const (
+	SortBySize        = "size"
+	SortByClusterSize = "cluster-size"
+)
+
+// Default number of objects to fetch per list page. Nodes carry their image lists
+// and are much larger than pods, so they are fetched in smaller pages. ReplicaSets,
+// Jobs and Namespaces are always paged with their defaults, whatever the pod and
+// node page sizes.
+const (
+	DefaultPodPageSize       = 500
+	DefaultNodePageSize      = 100
+	DefaultOwnerPageSize     = 500
+	DefaultNamespacePageSize = 500
+)
+
 // AnalysisConfig holds configuration for image analysis
 type AnalysisConfig struct {
-	PodPageSize int64 // Number of pods to fetch per page
+	PodPageSize    int64      // Number of pods to fetch per page; 0 lists all pods at once from the watch cache
+	NodePageSize   int64      // Number of nodes to fetch per page; 0 lists all nodes at once from the watch cache
+	GroupBy        string     // Attribute image sizes to a grouping ("namespace", "workload", "registry", "repository" or "container-type"); empty disables grouping
+	ContainerTypes []string   // Only analyze images of containers of these types; empty analyzes all containers
+	PodFilter      PodFilter  // Field selector and phases pods must match
+	NodeFilter     NodeFilter // Nodes whose images, and whose pods, are analyzed
+	ListPods       bool       // Always list pods, so images carry the namespaces using them and pod image references are kept
+	NamespaceUsage bool       // Attribute image sizes to namespaces whatever the grouping
+	TagHygiene     bool       // Check the image references of pods for :latest or missing tags, digest pins and version sprawl
+	TagDrift       bool       // Report tags used by pods that resolve to different digests across nodes and pods
 }
 
 // DefaultAnalysisConfig returns default configuration
 func DefaultAnalysisConfig() *AnalysisConfig {
 	return &AnalysisConfig{
-		PodPageSize: 500,
+		PodPageSize:  DefaultPodPageSize,
+		NodePageSize: DefaultNodePageSize,
 	}
 }
 
 // PerformanceMetrics holds timing and performance data
+// Durations encode as nanoseconds in JSON.
 type PerformanceMetrics struct {
-	PodQueryTime       time.Duration
-	NodeQueryTime      time.Duration
-	ImageAnalysisTime  time.Duration
-	TotalTime          time.Duration
-	ImagesProcessed    int
-	ImagesFailed       int
-	ImagesInaccessible int
-	CacheHits          int
-	CacheMisses        int
+	PodQueryTime       time.Duration `json:"podQueryTime"`
+	NodeQueryTime      time.Duration `json:"nodeQueryTime"`
+	OwnerQueryTime     time.Duration `json:"ownerQueryTime"`
+	ImageAnalysisTime  time.Duration `json:"imageAnalysisTime"`
+	QueryTime          time.Duration `json:"queryTime"`       // Wall-clock time of the cluster queries, which run concurrently
+	SummedQueryTime    time.Duration `json:"summedQueryTime"` // Sum of the time of each cluster query, as if run one after another
+	TotalTime          time.Duration `json:"totalTime"`
+	ImagesProcessed    int           `json:"imagesProcessed"`
+	ImagesFailed       int           `json:"imagesFailed"`
+	ImagesInaccessible int           `json:"imagesInaccessible"`
+	CacheHits          int           `json:"cacheHits"`
+	CacheMisses        int           `json:"cacheMisses"`
 }
@@ -0,0 +1,348 @@
+package types
+
+import (
+	"sort"
+)
+
+// NamespaceUsage holds the image size attribution for a single namespace
+type NamespaceUsage struct {
+	Namespace   string `json:"namespace"`
+	ImageCount  int    `json:"imageCount"`
+	UniqueBytes int64  `json:"uniqueBytes"` // Bytes of images used only by this namespace
+	SharedBytes int64  `json:"sharedBytes"` // Bytes of images also used by other namespaces
+}
+
+// TotalBytes returns the bytes of all images used by the namespace
+func (nu NamespaceUsage) TotalBytes() int64 {
+	return nu.UniqueBytes + nu.SharedBytes
+}
+
+// WorkloadUsage holds the image size attribution for a single workload
+type WorkloadUsage struct {
+	Workload
+	Replicas   int      `json:"replicas"`   // Number of pods observed for the workload
+	NodeCount  int      `json:"nodeCount"`  // Number of distinct nodes running the workload's pods
+	Images     []string `json:"images"`     // Images used by the workload, sorted by name
+	TotalBytes int64    `json:"totalBytes"` // Bytes of all images used by the workload
+}
+
+// RegistryUsage holds the image size attribution for a single registry or repository
+type RegistryUsage struct {
+	Name         string  `json:"name"`         // Registry host, or registry/repository
+	ImageCount   int     `json:"imageCount"`   // Number of images pulled from the registry or repository
+	TotalBytes   int64   `json:"totalBytes"`   // Bytes of the images, each counted once
+	ClusterBytes int64   `json:"clusterBytes"` // Bytes of the images across all nodes holding them
+	Share        float64 `json:"share"`        // Fraction of the bytes of all images across all nodes
+}
+
+// ContainerTypeUsage holds the image size attribution for a single container type
+type ContainerTypeUsage struct {
+	Type         string `json:"type"`         // ContainerType* value
+	ImageCount   int    `json:"imageCount"`   // Number of images run by containers of the type
+	TotalBytes   int64  `json:"totalBytes"`   // Bytes of the images, each counted once
+	ClusterBytes int64  `json:"clusterBytes"` // Bytes of the images across all nodes holding them
+}
+
+// PodImages folds pods into the image references the analysis needs, so pods can
+// be processed page by page as they are listed instead of being held in memory
+type PodImages struct {
+	Pods       int                        // Number of pods added
+	Images     map[string]bool            // Image references used by the pods
+	ImageIDs   map[string]string          // First imageID reported for each image reference
+	Namespaces map[string]map[string]bool // Image references used by the pods of each namespace
+	Types      map[string]map[string]bool // Container types running each image reference
+}
+
+// NewPodImages creates a PodImages holding the given pods
+func NewPodImages(pods ...Pod) *PodImages {
+	pi := &PodImages{
+		Images:     make(map[string]bool),
+		ImageIDs:   make(map[string]string),
+		Namespaces: make(map[string]map[string]bool),
+		Types:      make(map[string]map[string]bool),
+	}
+	for _, pod := range pods {
+		pi.Add(pod)
+	}
+	return pi
+}
+
+// Add folds the image references of a pod into the set
+func (pi *PodImages) Add(pod Pod) {
+	pi.Pods++
+	refs, exists := pi.Namespaces[pod.Namespace]
+	if !exists {
+		refs = make(map[string]bool)
+		pi.Namespaces[pod.Namespace] = refs
+	}
+	for _, ref := range pod.Images {
+		pi.Images[ref] = true
+		refs[ref] = true
+	}
+	for _, c := range pod.Containers {
+		if pi.Types[c.Image] == nil {
+			pi.Types[c.Image] = make(map[string]bool)
+		}
+		pi.Types[c.Image][c.Type] = true
+	}
+	for ref, imageID := range pod.ImageIDs {
+		if _, exists := pi.ImageIDs[ref]; !exists {
+			pi.ImageIDs[ref] = imageID
+		}
+	}
+}
+
+// namespaceImages returns the reported image names used by each namespace.
+// resolved maps each pod image reference to the reported image name.
+func (pi *PodImages) namespaceImages(resolved map[string]string) map[string]map[string]bool {
+	namespaceImages := make(map[string]map[string]bool, len(pi.Namespaces))
+	for namespace, refs := range pi.Namespaces {
+		images := make(map[string]bool, len(refs))
+		for ref := range refs {
+			name, ok := resolved[ref]
+			if !ok {
+				name = ref
+			}
+			images[name] = true
+		}
+		namespaceImages[namespace] = images
+	}
+	return namespaceImages
+}
+
+// ImageNamespaces returns the namespaces of the pods using each image, sorted by name.
+// resolved maps each pod image reference to the reported image name.
+func (pi *PodImages) ImageNamespaces(resolved map[string]string) map[string][]string {
+	namespaces := make(map[string][]string)
+	for namespace, images := range pi.namespaceImages(resolved) {
+		for name := range images {
+			namespaces[name] = append(namespaces[name], namespace)
+		}
+	}
+	for name := range namespaces {
+		sort.Strings(namespaces[name])
+	}
+	return namespaces
+}
+
+// ImageContainerTypes returns the types of the containers running each image, in
+// the order of ContainerTypes. resolved maps each pod image reference to the
+// reported image name.
+func (pi *PodImages) ImageContainerTypes(resolved map[string]string) map[string][]string {
+	imageTypes := make(map[string]map[string]bool)
+	for ref, refTypes := range pi.Types {
+		name, ok := resolved[ref]
+		if !ok {
+			continue
+		}
+		if imageTypes[name] == nil {
+			imageTypes[name] = make(map[string]bool)
+		}
+		for t := range refTypes {
+			imageTypes[name][t] = true
+		}
+	}
+
+	containerTypes := make(map[string][]string, len(imageTypes))
+	for name, set := range imageTypes {
+		for _, t := range ContainerTypes {
+			if set[t] {
+				containerTypes[name] = append(containerTypes[name], t)
+			}
+		}
+	}
+	return containerTypes
+}
+
+// AttributeNamespaces attributes image sizes to the namespaces of the pods using them.
+// resolved maps each pod image reference to the reported image name, and sizes maps
+// reported image names to their size in bytes. Results are sorted by unique bytes
+// (descending) so the namespaces with the largest exclusive footprint come first.
+func (pi *PodImages) AttributeNamespaces(resolved map[string]string, sizes map[string]int64) []NamespaceUsage {
+	namespaceImages := pi.namespaceImages(resolved)
+	imageNamespaces := make(map[string]int)
+	for _, images := range namespaceImages {
+		for name := range images {
+			imageNamespaces[name]++
+		}
+	}
+
+	usage := make([]NamespaceUsage, 0, len(namespaceImages))
+	for namespace, images := range namespaceImages {
+		nu := NamespaceUsage{
+			Namespace:  namespace,
+			ImageCount: len(images),
+		}
+		for name := range images {
+			if imageNamespaces[name] > 1 {
+				nu.SharedBytes += sizes[name]
+			} else {
+				nu.UniqueBytes += sizes[name]
+			}
+		}
+		usage = append(usage, nu)
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].UniqueBytes != usage[j].UniqueBytes {
+			return usage[i].UniqueBytes > usage[j].UniqueBytes
+		}
+		return usage[i].Namespace < usage[j].Namespace
+	})
+
+	return usage
+}
+
+// AttributeWorkloads attributes image sizes to the top-level workloads controlling
+// the given pods. owners maps intermediate controllers to their own controller (see
+// Pod.ResolveWorkload). Results are sorted by total bytes (descending).
+func AttributeWorkloads(pods []Pod, owners map[Workload]Workload, resolved map[string]string, sizes map[string]int64) []WorkloadUsage {
+	type workloadPods struct {
+		replicas int
+		nodes    map[string]bool
+		images   map[string]bool
+	}
+
+	groups := make(map[Workload]*workloadPods)
+	for _, pod := range pods {
+		workload := pod.ResolveWorkload(owners)
+		group, exists := groups[workload]
+		if !exists {
+			group = &workloadPods{
+				nodes:  make(map[string]bool),
+				images: make(map[string]bool),
+			}
+			groups[workload] = group
+		}
+		group.replicas++
+		if pod.NodeName != "" {
+			group.nodes[pod.NodeName] = true
+		}
+		for _, ref := range pod.Images {
+			name, ok := resolved[ref]
+			if !ok {
+				name = ref
+			}
+			group.images[name] = true
+		}
+	}
+
+	usage := make([]WorkloadUsage, 0, len(groups))
+	for workload, group := range groups {
+		wu := WorkloadUsage{
+			Workload:  workload,
+			Replicas:  group.replicas,
+			NodeCount: len(group.nodes),
+			Images:    make([]string, 0, len(group.images)),
+		}
+		for name := range group.images {
+			wu.Images = append(wu.Images, name)
+			wu.TotalBytes += sizes[name]
+		}
+		sort.Strings(wu.Images)
+		usage = append(usage, wu)
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].TotalBytes != usage[j].TotalBytes {
+			return usage[i].TotalBytes > usage[j].TotalBytes
+		}
+		if usage[i].Namespace != usage[j].Namespace {
+			return usage[i].Namespace < usage[j].Namespace
+		}
+		return usage[i].String() < usage[j].String()
+	})
+
+	return usage
+}
+
+// AttributeRegistries attributes image sizes to the registries the images are
+// pulled from. Results are sorted by cluster bytes (descending).
+func AttributeRegistries(images []Image) []RegistryUsage {
+	return attributeImages(images, func(img Image) string {
+		return img.Registry
+	})
+}
+
+// AttributeRepositories attributes image sizes to the repositories the images are
+// pulled from, across all tags. Results are sorted by cluster bytes (descending).
+func AttributeRepositories(images []Image) []RegistryUsage {
+	return attributeImages(images, func(img Image) string {
+		if img.Repository == "" {
+			return img.Registry
+		}
+		return img.Registry + "/" + img.Repository
+	})
+}
+
+// attributeImages sums image sizes per group. Inaccessible images are left out
+// since their size is unknown, and images without a registry are grouped as "unknown".
+func attributeImages(images []Image, key func(Image) string) []RegistryUsage {
+	groups := make(map[string]*RegistryUsage)
+	var clusterBytes int64
+	for _, img := range images {
+		if img.Inaccessible {
+			continue
+		}
+		name := key(img)
+		if img.Registry == "" {
+			name = "unknown"
+		}
+		group, exists := groups[name]
+		if !exists {
+			group = &RegistryUsage{Name: name}
+			groups[name] = group
+		}
+		group.ImageCount++
+		group.TotalBytes += img.Size
+		group.ClusterBytes += img.ClusterSize
+		clusterBytes += img.ClusterSize
+	}
+
+	usage := make([]RegistryUsage, 0, len(groups))
+	for _, group := range groups {
+		if clusterBytes > 0 {
+			group.Share = float64(group.ClusterBytes) / float64(clusterBytes)
+		}
+		usage = append(usage, *group)
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].ClusterBytes != usage[j].ClusterBytes {
+			return usage[i].ClusterBytes > usage[j].ClusterBytes
+		}
+		return usage[i].Name < usage[j].Name
+	})
+
+	return usage
+}
+
+// AttributeContainerTypes attributes image sizes to the types of the containers
+// running them. Images run by containers of several types count toward each type.
+// Results follow the order of ContainerTypes, leaving out types without images.
+func AttributeContainerTypes(images []Image) []ContainerTypeUsage {
+	byType := make(map[string]*ContainerTypeUsage)
+	for _, img := range images {
+		if img.Inaccessible {
+			continue
+		}
+		for _, t := range img.ContainerTypes {
+			usage, exists := byType[t]
+			if !exists {
+				usage = &ContainerTypeUsage{Type: t}
+				byType[t] = usage
+			}
+			usage.ImageCount++
+			usage.TotalBytes += img.Size
+			usage.ClusterBytes += img.ClusterSize
+		}
+	}
+
+	var usage []ContainerTypeUsage
+	for _, t := range ContainerTypes {
+		if u, exists := byType[t]; exists {
+			usage = append(usage, *u)
+		}
+	}
+	return usage
+}
@@ -0,0 +1,180 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributeNamespaces(t *testing.T) {
+	pods := []Pod{
+		{Name: "web", Namespace: "team-a", Images: []string{"nginx", "app-a:v1"}},
+		{Name: "web-2", Namespace: "team-a", Images: []string{"nginx"}},
+		{Name: "api", Namespace: "team-b", Images: []string{"docker.io/library/nginx:latest", "app-b:v1"}},
+		{Name: "job", Namespace: "team-c", Images: []string{"missing:v1"}},
+	}
+	resolved := map[string]string{
+		"nginx":                          "docker.io/library/nginx:latest",
+		"docker.io/library/nginx:latest": "docker.io/library/nginx:latest",
+		"app-a:v1":                       "app-a:v1",
+		"app-b:v1":                       "app-b:v1",
+		"missing:v1":                     "missing:v1",
+	}
+	sizes := map[string]int64{
+		"docker.io/library/nginx:latest": 100000000,
+		"app-a:v1":                       300000000,
+		"app-b:v1":                       200000000,
+	}
+
+	usage := NewPodImages(pods...).AttributeNamespaces(resolved, sizes)
+
+	assert.Equal(t, []NamespaceUsage{
+		{Namespace: "team-a", ImageCount: 2, UniqueBytes: 300000000, SharedBytes: 100000000},
+		{Namespace: "team-b", ImageCount: 2, UniqueBytes: 200000000, SharedBytes: 100000000},
+		{Namespace: "team-c", ImageCount: 1, UniqueBytes: 0, SharedBytes: 0},
+	}, usage)
+	assert.Equal(t, int64(400000000), usage[0].TotalBytes())
+}
+
+func TestImageNamespaces(t *testing.T) {
+	pods := []Pod{
+		{Name: "web", Namespace: "team-b", Images: []string{"nginx"}},
+		{Name: "api", Namespace: "team-a", Images: []string{"docker.io/library/nginx:latest", "app:v1"}},
+		{Name: "api-2", Namespace: "team-a", Images: []string{"app:v1"}},
+	}
+	resolved := map[string]string{
+		"nginx":                          "docker.io/library/nginx:latest",
+		"docker.io/library/nginx:latest": "docker.io/library/nginx:latest",
+	}
+
+	assert.Equal(t, map[string][]string{
+		"docker.io/library/nginx:latest": {"team-a", "team-b"},
+		"app:v1":                         {"team-a"},
+	}, NewPodImages(pods...).ImageNamespaces(resolved))
+}
+
+func TestAttributeNamespaces_Empty(t *testing.T) {
+	usage := NewPodImages().AttributeNamespaces(map[string]string{}, map[string]int64{})
+	assert.Empty(t, usage)
+}
+
+func TestAttributeRegistries(t *testing.T) {
+	images := []Image{
+		{Name: "docker.io/library/nginx:1.21", Registry: "docker.io", Repository: "library/nginx", Size: 100, ClusterSize: 300},
+		{Name: "docker.io/library/nginx:1.22", Registry: "docker.io", Repository: "library/nginx", Size: 100, ClusterSize: 100},
+		{Name: "ghcr.io/acme/api:v1", Registry: "ghcr.io", Repository: "acme/api", Size: 200, ClusterSize: 600},
+		{Name: "ghcr.io/acme/gone:v1", Registry: "ghcr.io", Repository: "acme/gone", Inaccessible: true},
+	}
+
+	assert.Equal(t, []RegistryUsage{
+		{Name: "ghcr.io", ImageCount: 1, TotalBytes: 200, ClusterBytes: 600, Share: 0.6},
+		{Name: "docker.io", ImageCount: 2, TotalBytes: 200, ClusterBytes: 400, Share: 0.4},
+	}, AttributeRegistries(images))
+
+	assert.Equal(t, []RegistryUsage{
+		{Name: "ghcr.io/acme/api", ImageCount: 1, TotalBytes: 200, ClusterBytes: 600, Share: 0.6},
+		{Name: "docker.io/library/nginx", ImageCount: 2, TotalBytes: 200, ClusterBytes: 400, Share: 0.4},
+	}, AttributeRepositories(images))
+
+	assert.Empty(t, AttributeRegistries(nil))
+}
+
+func TestAttributeContainerTypes(t *testing.T) {
+	pods := []Pod{
+		{Name: "web", Namespace: "default", Containers: []PodContainer{
+			{Name: "app", Image: "nginx", Type: ContainerTypeRegular},
+			{Name: "debugger", Image: "busybox", Type: ContainerTypeEphemeral},
+		}},
+		{Name: "job", Namespace: "default", Containers: []PodContainer{
+			{Name: "init", Image: "nginx", Type: ContainerTypeInit},
+		}},
+	}
+	resolved := map[string]string{
+		"nginx":   "docker.io/library/nginx:latest",
+		"busybox": "docker.io/library/busybox:latest",
+	}
+
+	containerTypes := NewPodImages(pods...).ImageContainerTypes(resolved)
+	assert.Equal(t, map[string][]string{
+		"docker.io/library/nginx:latest":   {ContainerTypeRegular, ContainerTypeInit},
+		"docker.io/library/busybox:latest": {ContainerTypeEphemeral},
+	}, containerTypes)
+
+	images := []Image{
+		{Name: "docker.io/library/nginx:latest", Size: 100, ClusterSize: 300, ContainerTypes: containerTypes["docker.io/library/nginx:latest"]},
+		{Name: "docker.io/library/busybox:latest", Size: 10, ClusterSize: 10, ContainerTypes: containerTypes["docker.io/library/busybox:latest"]},
+	}
+	assert.Equal(t, []ContainerTypeUsage{
+		{Type: ContainerTypeRegular, ImageCount: 1, TotalBytes: 100, ClusterBytes: 300},
+		{Type: ContainerTypeInit, ImageCount: 1, TotalBytes: 100, ClusterBytes: 300},
+		{Type: ContainerTypeEphemeral, ImageCount: 1, TotalBytes: 10, ClusterBytes: 10},
+	}, AttributeContainerTypes(images))
+}
+
+func TestAttributeWorkloads(t *testing.T) {
+	rs := Workload{Kind: "ReplicaSet", Namespace: "default", Name: "web-5d4f8"}
+	ds := Workload{Kind: "DaemonSet", Namespace: "kube-system", Name: "agent"}
+	owners := map[Workload]Workload{
+		rs: {Kind: "Deployment", Namespace: "default", Name: "web"},
+	}
+	pods := []Pod{
+		{Name: "web-1", Namespace: "default", NodeName: "node1", Owner: &rs, Images: []string{"nginx:1.21", "sidecar:v1"}},
+		{Name: "web-2", Namespace: "default", NodeName: "node2", Owner: &rs, Images: []string{"nginx:1.21", "sidecar:v1"}},
+		{Name: "web-3", Namespace: "default", NodeName: "node2", Owner: &rs, Images: []string{"nginx:1.21", "sidecar:v1"}},
+		{Name: "agent-1", Namespace: "kube-system", NodeName: "node1", Owner: &ds, Images: []string{"agent:v2"}},
+	}
+	resolved := map[string]string{
+		"nginx:1.21": "docker.io/library/nginx:1.21",
+		"sidecar:v1": "sidecar:v1",
+		"agent:v2":   "agent:v2",
+	}
+	sizes := map[string]int64{
+		"docker.io/library/nginx:1.21": 100000000,
+		"sidecar:v1":                   20000000,
+		"agent:v2":                     500000000,
+	}
+
+	usage := AttributeWorkloads(pods, owners, resolved, sizes)
+
+	assert.Equal(t, []WorkloadUsage{
+		{
+			Workload:   ds,
+			Replicas:   1,
+			NodeCount:  1,
+			Images:     []string{"agent:v2"},
+			TotalBytes: 500000000,
+		},
+		{
+			Workload:   Workload{Kind: "Deployment", Namespace: "default", Name: "web"},
+			Replicas:   3,
+			NodeCount:  2,
+			Images:     []string{"docker.io/library/nginx:1.21", "sidecar:v1"},
+			TotalBytes: 120000000,
+		},
+	}, usage)
+}
+
+func TestPodImages(t *testing.T) {
+	pi := NewPodImages(
+		Pod{Name: "web", Namespace: "team-a", Images: []string{"nginx", "app-a:v1"},
+			ImageIDs: map[string]string{"nginx": "docker.io/library/nginx@sha256:aaa"}},
+		Pod{Name: "web-2", Namespace: "team-a", Images: []string{"nginx"},
+			ImageIDs: map[string]string{"nginx": "docker.io/library/nginx@sha256:bbb"}},
+	)
+	pi.Add(Pod{Name: "api", Namespace: "team-b", Images: []string{"nginx"}})
+
+	assert.Equal(t, 3, pi.Pods)
+	assert.Equal(t, map[string]bool{"nginx": true, "app-a:v1": true}, pi.Images)
+	// The first imageID reported for a reference is kept
+	assert.Equal(t, map[string]string{"nginx": "docker.io/library/nginx@sha256:aaa"}, pi.ImageIDs)
+	assert.Equal(t, map[string]map[string]bool{
+		"team-a": {"nginx": true, "app-a:v1": true},
+		"team-b": {"nginx": true},
+	}, pi.Namespaces)
+
+	resolved := map[string]string{"nginx": "docker.io/library/nginx:latest"}
+	assert.Equal(t, map[string][]string{
+		"docker.io/library/nginx:latest": {"team-a", "team-b"},
+		"app-a:v1":                       {"team-a"},
+	}, pi.ImageNamespaces(resolved))
+}
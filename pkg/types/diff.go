@@ -0,0 +1,274 @@
+package types
+
+import (
+	"slices"
+	"sort"
+)
+
+// SizeChange holds the total and unique image bytes of a grouping (a namespace,
+// a registry, or the whole analysis) before and after a change
+type SizeChange struct {
+	Name          string `json:"name,omitempty"`
+	OldTotalSize  int64  `json:"oldTotalSize"`
+	NewTotalSize  int64  `json:"newTotalSize"`
+	OldUniqueSize int64  `json:"oldUniqueSize"`
+	NewUniqueSize int64  `json:"newUniqueSize"`
+}
+
+// TotalDelta returns the change in total bytes
+func (c SizeChange) TotalDelta() int64 {
+	return c.NewTotalSize - c.OldTotalSize
+}
+
+// UniqueDelta returns the change in unique bytes
+func (c SizeChange) UniqueDelta() int64 {
+	return c.NewUniqueSize - c.OldUniqueSize
+}
+
+// changed reports whether either size differs between the two analyses
+func (c SizeChange) changed() bool {
+	return c.TotalDelta() != 0 || c.UniqueDelta() != 0
+}
+
+// RepositoryChange describes a repository present in both analyses whose tags or size changed
+type RepositoryChange struct {
+	Repository string   `json:"repository"`
+	OldTags    []string `json:"oldTags"`
+	NewTags    []string `json:"newTags"`
+	OldSize    int64    `json:"oldSize"`
+	NewSize    int64    `json:"newSize"`
+}
+
+// SizeDelta returns the change in bytes of the repository's images
+func (c RepositoryChange) SizeDelta() int64 {
+	return c.NewSize - c.OldSize
+}
+
+// ImageChange describes an image present in both analyses under the same name whose
+// size changed, e.g. a mutable tag pushed again with different content
+type ImageChange struct {
+	Name    string `json:"name"`
+	OldSize int64  `json:"oldSize"`
+	NewSize int64  `json:"newSize"`
+}
+
+// SizeDelta returns the change in bytes of the image
+func (c ImageChange) SizeDelta() int64 {
+	return c.NewSize - c.OldSize
+}
+
+// ImageDiff holds the differences between two image analyses
+type ImageDiff struct {
+	Summary      SizeChange         `json:"summary"`
+	Added        []Image            `json:"added"`
+	Removed      []Image            `json:"removed"`
+	Changed      []ImageChange      `json:"changed"`
+	Repositories []RepositoryChange `json:"repositories"`
+	Namespaces   []SizeChange       `json:"namespaces"`
+	Registries   []SizeChange       `json:"registries"`
+}
+
+// HasChanges reports whether the two analyses differ
+func (d *ImageDiff) HasChanges() bool {
+	return d.Summary.changed() || len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0 ||
+		len(d.Repositories) > 0 || len(d.Namespaces) > 0 || len(d.Registries) > 0
+}
+
+// DiffAnalyses compares two image analyses. Images are matched by name; repositories
+// present in both analyses are compared across tags. Namespace changes are only
+// reported for namespaces attributed in either analysis (see --group-by namespace).
+func DiffAnalyses(oldAnalysis, newAnalysis *ImageAnalysis) *ImageDiff {
+	diff := &ImageDiff{
+		Summary: SizeChange{
+			OldTotalSize:  oldAnalysis.ClusterSize,
+			NewTotalSize:  newAnalysis.ClusterSize,
+			OldUniqueSize: oldAnalysis.UniqueSize,
+			NewUniqueSize: newAnalysis.UniqueSize,
+		},
+		Added:        []Image{},
+		Removed:      []Image{},
+		Changed:      []ImageChange{},
+		Repositories: []RepositoryChange{},
+		Namespaces:   []SizeChange{},
+		Registries:   []SizeChange{},
+	}
+
+	oldImages := oldAnalysis.GetUniqueImages()
+	newImages := newAnalysis.GetUniqueImages()
+
+	for name, img := range newImages {
+		oldImg, ok := oldImages[name]
+		if !ok {
+			diff.Added = append(diff.Added, img)
+		} else if oldImg.Size != img.Size {
+			diff.Changed = append(diff.Changed, ImageChange{Name: name, OldSize: oldImg.Size, NewSize: img.Size})
+		}
+	}
+	for name, img := range oldImages {
+		if _, ok := newImages[name]; !ok {
+			diff.Removed = append(diff.Removed, img)
+		}
+	}
+	sortImagesByClusterSize(diff.Added)
+	sortImagesByClusterSize(diff.Removed)
+	sortImageChanges(diff.Changed)
+
+	diff.Repositories = diffRepositories(oldImages, newImages)
+
+	// Per-registry totals
+	registries := make(map[string]*SizeChange)
+	registry := func(img Image) *SizeChange {
+		name := img.Registry
+		if name == "" {
+			name = "unknown"
+		}
+		if registries[name] == nil {
+			registries[name] = &SizeChange{Name: name}
+		}
+		return registries[name]
+	}
+	for _, img := range oldImages {
+		change := registry(img)
+		change.OldTotalSize += img.ClusterSize
+		change.OldUniqueSize += img.Size
+	}
+	for _, img := range newImages {
+		change := registry(img)
+		change.NewTotalSize += img.ClusterSize
+		change.NewUniqueSize += img.Size
+	}
+	diff.Registries = changedGroups(registries)
+
+	// Per-namespace totals, from the namespace attribution of each analysis
+	namespaces := make(map[string]*SizeChange)
+	for _, ns := range oldAnalysis.Namespaces {
+		namespaces[ns.Namespace] = &SizeChange{
+			Name:          ns.Namespace,
+			OldTotalSize:  ns.TotalBytes(),
+			OldUniqueSize: ns.UniqueBytes,
+		}
+	}
+	for _, ns := range newAnalysis.Namespaces {
+		change := namespaces[ns.Namespace]
+		if change == nil {
+			change = &SizeChange{Name: ns.Namespace}
+			namespaces[ns.Namespace] = change
+		}
+		change.NewTotalSize = ns.TotalBytes()
+		change.NewUniqueSize = ns.UniqueBytes
+	}
+	diff.Namespaces = changedGroups(namespaces)
+
+	return diff
+}
+
+// diffRepositories compares the tags and sizes of repositories present in both image sets
+func diffRepositories(oldImages, newImages map[string]Image) []RepositoryChange {
+	type repoImages struct {
+		tags []string
+		size int64
+	}
+	group := func(images map[string]Image) map[string]*repoImages {
+		repos := make(map[string]*repoImages)
+		for _, img := range images {
+			if img.Repository == "" {
+				continue
+			}
+			key := img.Registry + "/" + img.Repository
+			if repos[key] == nil {
+				repos[key] = &repoImages{}
+			}
+			tag := img.Tag
+			if tag == "" {
+				tag = img.Digest
+			}
+			repos[key].tags = append(repos[key].tags, tag)
+			repos[key].size += img.Size
+		}
+		return repos
+	}
+
+	oldRepos := group(oldImages)
+	newRepos := group(newImages)
+
+	changes := []RepositoryChange{}
+	for key, oldRepo := range oldRepos {
+		newRepo, ok := newRepos[key]
+		if !ok {
+			continue
+		}
+		sort.Strings(oldRepo.tags)
+		sort.Strings(newRepo.tags)
+		if oldRepo.size == newRepo.size && slices.Equal(oldRepo.tags, newRepo.tags) {
+			continue
+		}
+		changes = append(changes, RepositoryChange{
+			Repository: key,
+			OldTags:    oldRepo.tags,
+			NewTags:    newRepo.tags,
+			OldSize:    oldRepo.size,
+			NewSize:    newRepo.size,
+		})
+	}
+
+	// Sort by absolute size change (descending), then by name for stable output
+	sort.Slice(changes, func(i, j int) bool {
+		di, dj := abs(changes[i].SizeDelta()), abs(changes[j].SizeDelta())
+		if di != dj {
+			return di > dj
+		}
+		return changes[i].Repository < changes[j].Repository
+	})
+
+	return changes
+}
+
+// changedGroups returns the groups whose sizes changed, sorted by absolute total
+// change (descending), then by name
+func changedGroups(groups map[string]*SizeChange) []SizeChange {
+	changes := []SizeChange{}
+	for _, change := range groups {
+		if change.changed() {
+			changes = append(changes, *change)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		di, dj := abs(changes[i].TotalDelta()), abs(changes[j].TotalDelta())
+		if di != dj {
+			return di > dj
+		}
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
+// sortImageChanges sorts image changes by absolute size change (descending), then by name
+func sortImageChanges(changes []ImageChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		di, dj := abs(changes[i].SizeDelta()), abs(changes[j].SizeDelta())
+		if di != dj {
+			return di > dj
+		}
+		return changes[i].Name < changes[j].Name
+	})
+}
+
+// sortImagesByClusterSize sorts images by bytes used across all nodes (descending), then by name
+func sortImagesByClusterSize(images []Image) {
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].ClusterSize != images[j].ClusterSize {
+			return images[i].ClusterSize > images[j].ClusterSize
+		}
+		return images[i].Name < images[j].Name
+	})
+}
+
+// abs returns the absolute value of n
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diffImage creates an image of the given size held by nodeCount nodes
+func diffImage(name string, size int64, nodeCount int) Image {
+	img := NewImage(name, size)
+	nodes := make([]string, nodeCount)
+	for i := range nodes {
+		nodes[i] = string(rune('a' + i))
+	}
+	img.SetNodes(nodes)
+	return *img
+}
+
+func TestDiffAnalyses(t *testing.T) {
+	oldAnalysis := &ImageAnalysis{
+		Images: []Image{
+			diffImage("nginx:1.21", 100, 2),
+			diffImage("gcr.io/team/app:v1", 500, 1),
+			diffImage("redis:6.2", 50, 1),
+		},
+		TotalSize:   650,
+		UniqueSize:  650,
+		ClusterSize: 750,
+		Namespaces: []NamespaceUsage{
+			{Namespace: "web", ImageCount: 1, UniqueBytes: 100},
+			{Namespace: "app", ImageCount: 1, UniqueBytes: 500},
+			{Namespace: "cache", ImageCount: 1, UniqueBytes: 50},
+		},
+	}
+	newAnalysis := &ImageAnalysis{
+		Images: []Image{
+			diffImage("nginx:1.21", 100, 2),
+			diffImage("gcr.io/team/app:v2", 800, 3),
+			diffImage("postgres:15", 200, 1),
+		},
+		TotalSize:   1100,
+		UniqueSize:  1100,
+		ClusterSize: 2800,
+		Namespaces: []NamespaceUsage{
+			{Namespace: "web", ImageCount: 1, UniqueBytes: 100},
+			{Namespace: "app", ImageCount: 1, UniqueBytes: 800},
+			{Namespace: "db", ImageCount: 1, UniqueBytes: 200},
+		},
+	}
+
+	diff := DiffAnalyses(oldAnalysis, newAnalysis)
+
+	assert.True(t, diff.HasChanges())
+	assert.Equal(t, int64(2050), diff.Summary.TotalDelta())
+	assert.Equal(t, int64(450), diff.Summary.UniqueDelta())
+
+	require.Len(t, diff.Added, 2)
+	assert.Equal(t, "gcr.io/team/app:v2", diff.Added[0].Name)
+	assert.Equal(t, "postgres:15", diff.Added[1].Name)
+
+	require.Len(t, diff.Removed, 2)
+	assert.Equal(t, "gcr.io/team/app:v1", diff.Removed[0].Name)
+	assert.Equal(t, "redis:6.2", diff.Removed[1].Name)
+
+	// nginx:1.21 keeps its name and size
+	assert.Empty(t, diff.Changed)
+
+	require.Len(t, diff.Repositories, 1)
+	repo := diff.Repositories[0]
+	assert.Equal(t, "gcr.io/team/app", repo.Repository)
+	assert.Equal(t, []string{"v1"}, repo.OldTags)
+	assert.Equal(t, []string{"v2"}, repo.NewTags)
+	assert.Equal(t, int64(300), repo.SizeDelta())
+
+	// Unchanged namespaces are omitted; largest change first
+	require.Len(t, diff.Namespaces, 3)
+	assert.Equal(t, "app", diff.Namespaces[0].Name)
+	assert.Equal(t, int64(300), diff.Namespaces[0].UniqueDelta())
+	assert.Equal(t, "db", diff.Namespaces[1].Name)
+	assert.Equal(t, int64(0), diff.Namespaces[1].OldTotalSize)
+	assert.Equal(t, "cache", diff.Namespaces[2].Name)
+	assert.Equal(t, int64(-50), diff.Namespaces[2].TotalDelta())
+
+	require.Len(t, diff.Registries, 2)
+	assert.Equal(t, "gcr.io", diff.Registries[0].Name)
+	assert.Equal(t, int64(2400-500), diff.Registries[0].TotalDelta())
+	assert.Equal(t, "docker.io", diff.Registries[1].Name)
+	assert.Equal(t, int64(150), diff.Registries[1].TotalDelta())
+}
+
+func TestDiffAnalyses_Identical(t *testing.T) {
+	analysis := &ImageAnalysis{
+		Images:      []Image{diffImage("nginx:1.21", 100, 1)},
+		TotalSize:   100,
+		UniqueSize:  100,
+		ClusterSize: 100,
+	}
+
+	diff := DiffAnalyses(analysis, analysis)
+
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Empty(t, diff.Changed)
+	assert.Empty(t, diff.Repositories)
+	assert.Empty(t, diff.Namespaces)
+	assert.Empty(t, diff.Registries)
+	assert.Equal(t, int64(0), diff.Summary.TotalDelta())
+	assert.False(t, diff.HasChanges())
+
+	// Empty lists are non-nil so they encode as [] rather than null
+	assert.NotNil(t, diff.Added)
+	assert.NotNil(t, diff.Changed)
+	assert.NotNil(t, diff.Registries)
+}
+
+func TestDiffAnalyses_ChangedSize(t *testing.T) {
+	// A mutable tag pushed again with different content keeps its name but changes size
+	oldAnalysis := &ImageAnalysis{Images: []Image{
+		diffImage("myapp:stable", 100, 1),
+		diffImage("redis:6.2", 50, 1),
+		diffImage("nginx:1.21", 80, 1),
+	}}
+	newAnalysis := &ImageAnalysis{Images: []Image{
+		diffImage("myapp:stable", 400, 1),
+		diffImage("redis:6.2", 40, 1),
+		diffImage("nginx:1.21", 80, 2),
+	}}
+
+	diff := DiffAnalyses(oldAnalysis, newAnalysis)
+
+	assert.True(t, diff.HasChanges())
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+
+	// Largest change first; a node count change alone is not a size change
+	require.Len(t, diff.Changed, 2)
+	assert.Equal(t, ImageChange{Name: "myapp:stable", OldSize: 100, NewSize: 400}, diff.Changed[0])
+	assert.Equal(t, int64(300), diff.Changed[0].SizeDelta())
+	assert.Equal(t, ImageChange{Name: "redis:6.2", OldSize: 50, NewSize: 40}, diff.Changed[1])
+	assert.Equal(t, int64(-10), diff.Changed[1].SizeDelta())
+}
+
+func TestDiffAnalyses_RepositoryNodeCountOnly(t *testing.T) {
+	// An image spreading to more nodes changes totals but not the repository's tags or size
+	oldAnalysis := &ImageAnalysis{Images: []Image{diffImage("nginx:1.21", 100, 1)}, TotalSize: 100, UniqueSize: 100, ClusterSize: 100}
+	newAnalysis := &ImageAnalysis{Images: []Image{diffImage("nginx:1.21", 100, 3)}, TotalSize: 100, UniqueSize: 100, ClusterSize: 300}
+
+	diff := DiffAnalyses(oldAnalysis, newAnalysis)
+
+	assert.True(t, diff.HasChanges())
+	assert.Empty(t, diff.Repositories)
+	require.Len(t, diff.Registries, 1)
+	assert.Equal(t, int64(200), diff.Registries[0].TotalDelta())
+	assert.Equal(t, int64(0), diff.Registries[0].UniqueDelta())
+}
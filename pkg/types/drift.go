@@ -0,0 +1,93 @@
+package types
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
+)
+
+// TagDrift holds a tag that resolves to more than one digest across nodes and pods,
+// so pods using the tag may run different code
+type TagDrift struct {
+	Image   string        `json:"image"`   // Fully qualified tagged reference, e.g. "docker.io/acme/app:stable"
+	Digests []DigestUsage `json:"digests"` // Sorted by digest
+}
+
+// DigestUsage holds the nodes and pods that resolved a tag to a digest
+type DigestUsage struct {
+	Digest string   `json:"digest"`
+	Nodes  []string `json:"nodes,omitempty"` // Nodes holding the digest under the tag, sorted
+	Pods   []string `json:"pods,omitempty"`  // Pods running the digest, in "namespace/name" form, sorted
+}
+
+// DetectTagDrift finds the tags used by the pods that resolve to more than one
+// digest. Digests come from the repository digests listed in node status and the
+// container status imageIDs of the pods. Digest-pinned references cannot drift and
+// are skipped, as are imageIDs without a repository digest, since a local image ID
+// never matches the digests reported by nodes.
+func DetectTagDrift(pods []Pod, index *NodeImageIndex) []TagDrift {
+	podDigests := make(map[string]map[string][]string)
+	for _, pod := range pods {
+		for _, ref := range pod.Images {
+			parsed, err := util.ParseImageReference(ref)
+			if err != nil || parsed.Digest != "" {
+				continue
+			}
+			name := parsed.String()
+			if podDigests[name] == nil {
+				podDigests[name] = make(map[string][]string)
+			}
+
+			imageID := pod.ImageIDs[ref]
+			if !strings.Contains(imageID, "@") {
+				continue
+			}
+			digest := util.ExtractDigest(imageID)
+			podDigests[name][digest] = append(podDigests[name][digest], pod.Namespace+"/"+pod.Name)
+		}
+	}
+
+	var drift []TagDrift
+	for name, pods := range podDigests {
+		nodes := index.tagDigests[name]
+
+		digests := make(map[string]bool)
+		for digest := range nodes {
+			digests[digest] = true
+		}
+		for digest := range pods {
+			digests[digest] = true
+		}
+		if len(digests) < 2 {
+			continue
+		}
+
+		td := TagDrift{Image: name}
+		for _, digest := range sortedKeys(digests) {
+			td.Digests = append(td.Digests, DigestUsage{
+				Digest: digest,
+				Nodes:  uniqueSorted(nodes[digest]),
+				Pods:   uniqueSorted(pods[digest]),
+			})
+		}
+		drift = append(drift, td)
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Image < drift[j].Image
+	})
+	return drift
+}
+
+// uniqueSorted returns the distinct values sorted, or nil if there are none
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return sortedKeys(set)
+}
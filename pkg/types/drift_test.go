@@ -0,0 +1,66 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectTagDrift(t *testing.T) {
+	oldDigest := "sha256:" + strings.Repeat("a", 64)
+	newDigest := "sha256:" + strings.Repeat("b", 64)
+
+	index := NewNodeImageIndex()
+	index.AddTagDigests([]string{"docker.io/acme/app@" + oldDigest, "docker.io/acme/app:stable"}, "node1")
+	index.AddTagDigests([]string{"docker.io/acme/app@" + newDigest, "docker.io/acme/app:stable"}, "node2")
+	index.AddTagDigests([]string{"docker.io/library/redis@" + oldDigest, "docker.io/library/redis:7"}, "node1")
+	index.AddTagDigests([]string{"docker.io/library/redis@" + oldDigest, "docker.io/library/redis:7"}, "node2")
+
+	pods := []Pod{
+		{Name: "app-1", Namespace: "web", Images: []string{"acme/app:stable", "redis:7"}, ImageIDs: map[string]string{
+			"acme/app:stable": "docker.io/acme/app@" + oldDigest,
+			"redis:7":         "docker.io/library/redis@" + oldDigest,
+		}},
+		{Name: "app-2", Namespace: "web", Images: []string{"acme/app:stable"}, ImageIDs: map[string]string{
+			"acme/app:stable": "docker.io/acme/app@" + newDigest,
+		}},
+		// Local image IDs cannot be compared with repository digests
+		{Name: "app-3", Namespace: "web", Images: []string{"acme/app:stable"}, ImageIDs: map[string]string{
+			"acme/app:stable": "sha256:" + strings.Repeat("c", 64),
+		}},
+		// Digest-pinned references cannot drift
+		{Name: "pinned", Namespace: "web", Images: []string{"acme/app@" + newDigest}},
+	}
+
+	assert.Equal(t, []TagDrift{
+		{
+			Image: "docker.io/acme/app:stable",
+			Digests: []DigestUsage{
+				{Digest: oldDigest, Nodes: []string{"node1"}, Pods: []string{"web/app-1"}},
+				{Digest: newDigest, Nodes: []string{"node2"}, Pods: []string{"web/app-2"}},
+			},
+		},
+	}, DetectTagDrift(pods, index))
+}
+
+func TestDetectTagDrift_PodsOnly(t *testing.T) {
+	oldDigest := "sha256:" + strings.Repeat("a", 64)
+	newDigest := "sha256:" + strings.Repeat("b", 64)
+
+	// Nodes without digest names still allow drift to be detected from pods
+	index := NewNodeImageIndex()
+	index.AddTagDigests([]string{"docker.io/acme/app:stable"}, "node1")
+
+	pods := []Pod{
+		{Name: "a", Namespace: "web", Images: []string{"acme/app:stable"}, ImageIDs: map[string]string{"acme/app:stable": "docker-pullable://acme/app@" + oldDigest}},
+		{Name: "b", Namespace: "api", Images: []string{"acme/app:stable"}, ImageIDs: map[string]string{"acme/app:stable": "docker-pullable://acme/app@" + newDigest}},
+	}
+
+	drift := DetectTagDrift(pods, index)
+
+	assert.Len(t, drift, 1)
+	assert.Equal(t, []string{"web/a"}, drift[0].Digests[0].Pods)
+	assert.Nil(t, drift[0].Digests[0].Nodes)
+	assert.Equal(t, []string{"api/b"}, drift[0].Digests[1].Pods)
+}
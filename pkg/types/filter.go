@@ -0,0 +1,66 @@
+package types
+
+import (
+	"strings"
+)
+
+// Pod phases accepted by PodFilter.Phases
+var PodPhases = []string{"Pending", "Running", "Succeeded", "Failed", "Unknown"}
+
+// PodFilter selects pods by fields the label selector cannot express
+type PodFilter struct {
+	FieldSelector string   // Field selector evaluated by the API server, e.g. "status.phase!=Succeeded"
+	Phases        []string // Pod phases to keep; empty keeps pods in every phase
+}
+
+// MatchesPhase reports whether the pod phase is selected
+func (f PodFilter) MatchesPhase(phase string) bool {
+	if len(f.Phases) == 0 {
+		return true
+	}
+	for _, p := range f.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeFilter selects the nodes whose images are analyzed. Pods are restricted to
+// the selected nodes as well.
+type NodeFilter struct {
+	Names    []string // Nodes to analyze; empty analyzes all nodes
+	Selector string   // Label selector the nodes must match
+}
+
+// IsAll reports whether the filter selects every node
+func (f NodeFilter) IsAll() bool {
+	return len(f.Names) == 0 && f.Selector == ""
+}
+
+// MatchesName reports whether the node is one of the named nodes. The selector
+// is not checked; it is evaluated when listing nodes.
+func (f NodeFilter) MatchesName(name string) bool {
+	if len(f.Names) == 0 {
+		return true
+	}
+	for _, n := range f.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a description of the selected nodes, e.g. "gpu-1, gpu-2" or
+// "All matching pool=gpu"
+func (f NodeFilter) String() string {
+	description := "All"
+	if len(f.Names) > 0 {
+		description = strings.Join(f.Names, ", ")
+	}
+	if f.Selector != "" {
+		description += " matching " + f.Selector
+	}
+	return description
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodFilter_MatchesPhase(t *testing.T) {
+	assert.True(t, PodFilter{}.MatchesPhase("Succeeded"))
+
+	filter := PodFilter{Phases: []string{"Running", "Pending"}}
+	assert.True(t, filter.MatchesPhase("Running"))
+	assert.False(t, filter.MatchesPhase("Succeeded"))
+}
+
+func TestNodeFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      NodeFilter
+		node        string
+		isAll       bool
+		matches     bool
+		description string
+	}{
+		{name: "all nodes", filter: NodeFilter{}, node: "node1", isAll: true, matches: true, description: "All"},
+		{name: "named node", filter: NodeFilter{Names: []string{"gpu-1", "gpu-2"}}, node: "gpu-2", matches: true, description: "gpu-1, gpu-2"},
+		{name: "other node", filter: NodeFilter{Names: []string{"gpu-1"}}, node: "node1", matches: false, description: "gpu-1"},
+		{name: "selector only", filter: NodeFilter{Selector: "pool=gpu"}, node: "node1", matches: true, description: "All matching pool=gpu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.isAll, tt.filter.IsAll())
+			assert.Equal(t, tt.matches, tt.filter.MatchesName(tt.node))
+			assert.Equal(t, tt.description, tt.filter.String())
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
+)
+
+// Tag hygiene issues of an image reference
+const (
+	TagIssueLatest   = "latest"   // Explicitly uses the :latest tag
+	TagIssueUntagged = "untagged" // Has no tag or digest, so it resolves to :latest
+	TagIssueUnpinned = "unpinned" // Is not pinned by digest
+)
+
+// TagHygiene holds the tag hygiene findings for the image references used by pods
+type TagHygiene struct {
+	Images []TagFinding    `json:"images,omitempty"` // Image references with at least one issue
+	Sprawl []VersionSprawl `json:"sprawl,omitempty"` // Repositories running more than one version
+}
+
+// TagFinding holds the tag hygiene issues of an image reference as written in pod specs
+type TagFinding struct {
+	Image      string   `json:"image"`
+	Issues     []string `json:"issues"`     // TagIssue* values
+	Namespaces []string `json:"namespaces"` // Namespaces of the pods using the reference, sorted
+}
+
+// VersionSprawl holds a repository that pods run at several tags or digests at once
+type VersionSprawl struct {
+	Repository string   `json:"repository"` // Registry and repository, e.g. "docker.io/library/nginx"
+	Versions   []string `json:"versions"`   // Tags, or digests for digest-only references, sorted
+	Namespaces []string `json:"namespaces"` // Namespaces of the pods running the repository, sorted
+}
+
+// HasIssues reports whether any tag hygiene issue was found
+func (h *TagHygiene) HasIssues() bool {
+	return h != nil && (len(h.Images) > 0 || len(h.Sprawl) > 0)
+}
+
+// TagHygiene checks the image references used by the pods for :latest or missing
+// tags, missing digest pins and repositories running several versions. References
+// are checked as written in pod specs, since the tag or digest a pod asks for is
+// lost once the image is resolved on a node. References that cannot be parsed are skipped.
+func (pi *PodImages) TagHygiene() *TagHygiene {
+	refNamespaces := make(map[string]map[string]bool)
+	for namespace, refs := range pi.Namespaces {
+		for ref := range refs {
+			if refNamespaces[ref] == nil {
+				refNamespaces[ref] = make(map[string]bool)
+			}
+			refNamespaces[ref][namespace] = true
+		}
+	}
+
+	type repository struct {
+		versions   map[string]bool
+		namespaces map[string]bool
+	}
+	repositories := make(map[string]*repository)
+
+	hygiene := &TagHygiene{}
+	for ref, namespaces := range refNamespaces {
+		parsed, err := util.ParseImageReference(ref)
+		if err != nil {
+			continue
+		}
+
+		var issues []string
+		switch {
+		case util.IsUntagged(ref):
+			issues = append(issues, TagIssueUntagged)
+		case parsed.Tag == util.DefaultTag && parsed.Digest == "":
+			issues = append(issues, TagIssueLatest)
+		}
+		if parsed.Digest == "" {
+			issues = append(issues, TagIssueUnpinned)
+		}
+		if len(issues) > 0 {
+			hygiene.Images = append(hygiene.Images, TagFinding{
+				Image:      ref,
+				Issues:     issues,
+				Namespaces: sortedKeys(namespaces),
+			})
+		}
+
+		// Bare digests have no repository to sprawl across
+		if parsed.Repository == "" {
+			continue
+		}
+		name := parsed.Registry + "/" + parsed.Repository
+		repo, exists := repositories[name]
+		if !exists {
+			repo = &repository{versions: make(map[string]bool), namespaces: make(map[string]bool)}
+			repositories[name] = repo
+		}
+		version := parsed.Tag
+		if version == "" {
+			version = parsed.Digest
+		}
+		repo.versions[version] = true
+		for namespace := range namespaces {
+			repo.namespaces[namespace] = true
+		}
+	}
+
+	for name, repo := range repositories {
+		if len(repo.versions) > 1 {
+			hygiene.Sprawl = append(hygiene.Sprawl, VersionSprawl{
+				Repository: name,
+				Versions:   sortedKeys(repo.versions),
+				Namespaces: sortedKeys(repo.namespaces),
+			})
+		}
+	}
+
+	sort.Slice(hygiene.Images, func(i, j int) bool {
+		return hygiene.Images[i].Image < hygiene.Images[j].Image
+	})
+	sort.Slice(hygiene.Sprawl, func(i, j int) bool {
+		if len(hygiene.Sprawl[i].Versions) != len(hygiene.Sprawl[j].Versions) {
+			return len(hygiene.Sprawl[i].Versions) > len(hygiene.Sprawl[j].Versions)
+		}
+		return hygiene.Sprawl[i].Repository < hygiene.Sprawl[j].Repository
+	})
+
+	return hygiene
+}
+
+// sortedKeys returns the keys of a set, sorted
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
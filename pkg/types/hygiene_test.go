@@ -0,0 +1,43 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodImages_TagHygiene(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	pods := []Pod{
+		{Name: "web", Namespace: "team-a", Images: []string{"nginx", "ghcr.io/acme/api:v1@" + digest}},
+		{Name: "web-2", Namespace: "team-b", Images: []string{"nginx", "nginx:1.21"}},
+		{Name: "api", Namespace: "prod", Images: []string{"ghcr.io/acme/api@" + digest, "redis:latest"}},
+		{Name: "bad", Namespace: "prod", Images: []string{"Invalid Ref"}},
+	}
+
+	hygiene := NewPodImages(pods...).TagHygiene()
+
+	assert.True(t, hygiene.HasIssues())
+	assert.Equal(t, []TagFinding{
+		{Image: "nginx", Issues: []string{TagIssueUntagged, TagIssueUnpinned}, Namespaces: []string{"team-a", "team-b"}},
+		{Image: "nginx:1.21", Issues: []string{TagIssueUnpinned}, Namespaces: []string{"team-b"}},
+		{Image: "redis:latest", Issues: []string{TagIssueLatest, TagIssueUnpinned}, Namespaces: []string{"prod"}},
+	}, hygiene.Images)
+	assert.Equal(t, []VersionSprawl{
+		{Repository: "docker.io/library/nginx", Versions: []string{"1.21", "latest"}, Namespaces: []string{"team-a", "team-b"}},
+		{Repository: "ghcr.io/acme/api", Versions: []string{digest, "v1"}, Namespaces: []string{"prod", "team-a"}},
+	}, hygiene.Sprawl)
+}
+
+func TestPodImages_TagHygiene_Clean(t *testing.T) {
+	pods := []Pod{
+		{Name: "api", Namespace: "prod", Images: []string{"ghcr.io/acme/api:v1@sha256:0123456789abcdef0123456789abcdef"}},
+		{Name: "job", Namespace: "prod", Images: []string{"sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"}},
+	}
+
+	hygiene := NewPodImages(pods...).TagHygiene()
+
+	assert.False(t, hygiene.HasIssues())
+	assert.Empty(t, hygiene.Sprawl)
+	assert.False(t, (*TagHygiene)(nil).HasIssues())
+}
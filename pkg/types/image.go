@@ -8,19 +8,51 @@ import (
 
 // Image represents a container image with its metadata
 type Image struct {
-	Name         string
-	Size         int64
-	Registry     string
-	Tag          string
-	Inaccessible bool // True if the image cannot be accessed
+	Name           string   `json:"name"`
+	Size           int64    `json:"size"`
+	Registry       string   `json:"registry"`
+	Repository     string   `json:"repository"`
+	Tag            string   `json:"tag"`
+	Digest         string   `json:"digest,omitempty"`
+	Nodes          []string `json:"nodes,omitempty"`          // Names of the nodes holding the image, sorted
+	NodeCount      int      `json:"nodeCount"`                // Number of nodes holding the image
+	ClusterSize    int64    `json:"clusterSize"`              // Bytes used across all nodes (Size × NodeCount)
+	Namespaces     []string `json:"namespaces,omitempty"`     // Namespaces of the pods using the image, set when pods are listed
+	ContainerTypes []string `json:"containerTypes,omitempty"` // Types of the containers running the image, set when pods are listed
+	Inaccessible   bool     `json:"inaccessible"`             // True if the image cannot be accessed
+}
+
+// SetNodes records the nodes holding the image and updates its node count and cluster size
+func (img *Image) SetNodes(nodes []string) {
+	img.Nodes = make([]string, 0, len(nodes))
+	seen := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if !seen[node] {
+			seen[node] = true
+			img.Nodes = append(img.Nodes, node)
+		}
+	}
+	sort.Strings(img.Nodes)
+	img.NodeCount = len(img.Nodes)
+	img.ClusterSize = img.Size * int64(img.NodeCount)
 }
 
 // ImageAnalysis represents the analysis results for images
 type ImageAnalysis struct {
-	Images      []Image
-	TotalSize   int64
-	UniqueSize  int64 // Size after deduplication
-	Performance *PerformanceMetrics
+	Images         []Image
+	TotalSize      int64
+	UniqueSize     int64 // Size after deduplication
+	ClusterSize    int64 // Bytes used across all nodes, counting every copy of an image
+	Performance    *PerformanceMetrics
+	Namespaces     []NamespaceUsage     // Per-namespace attribution, set when grouping by namespace or with AnalysisConfig.NamespaceUsage
+	Workloads      []WorkloadUsage      // Per-workload attribution, set when grouping by workload
+	Registries     []RegistryUsage      // Per-registry attribution, set when grouping by registry
+	Repositories   []RegistryUsage      // Per-repository attribution, set when grouping by repository
+	Violations     []PolicyViolation    // Policy violations, set when a policy is checked
+	TagHygiene     *TagHygiene          // Tag hygiene findings, set when tag hygiene is checked
+	TagDrift       []TagDrift           // Tags resolving to more than one digest, set when tag drift is checked
+	ContainerTypes []ContainerTypeUsage // Per-container-type attribution, set when grouping by container type
+	PodImages      *PodImages           // Image references of the listed pods, set when pods are listed
 }
 
 // GetUniqueImages returns a map of unique images by name
@@ -50,14 +82,42 @@ func (ia *ImageAnalysis) GetTopImagesBySize(n int) []Image {
 	return sorted[:n]
 }
 
+// NewImage creates an image entry with registry, repository, tag and digest
+// parsed from the image name. Names that cannot be parsed keep only the name.
+func NewImage(imageName string, size int64) *Image {
+	img := &Image{
+		Name: imageName,
+		Size: size,
+	}
+	if ref, err := util.ParseImageReference(imageName); err == nil {
+		img.Registry = ref.Registry
+		img.Repository = ref.Repository
+		img.Tag = ref.Tag
+		img.Digest = ref.Digest
+	}
+	return img
+}
+
+// GetTopImagesByClusterSize returns the top N images sorted by bytes used across all nodes
+func (ia *ImageAnalysis) GetTopImagesByClusterSize(n int) []Image {
+	if n > len(ia.Images) {
+		n = len(ia.Images)
+	}
+
+	// Create a copy to avoid modifying the original slice
+	sorted := make([]Image, len(ia.Images))
+	copy(sorted, ia.Images)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ClusterSize > sorted[j].ClusterSize
+	})
+
+	return sorted[:n]
+}
+
 // NewInaccessibleImage creates an image entry for an inaccessible image
 func NewInaccessibleImage(imageName string) *Image {
-	registry, tag := util.ExtractRegistryAndTag(imageName)
-	return &Image{
-		Name:         imageName,
-		Size:         0,
-		Registry:     registry,
-		Tag:          tag,
-		Inaccessible: true,
-	}
+	img := NewImage(imageName, 0)
+	img.Inaccessible = true
+	return img
 }
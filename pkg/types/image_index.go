@@ -0,0 +1,112 @@
+package types
+
+import (
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
+)
+
+// NodeImageIndex resolves image references to the images reported in node status.
+// Every name and digest of a node image is indexed so that pod images can be
+// matched by digest first and then by normalized reference.
+type NodeImageIndex struct {
+	Sizes      map[string]int64                // Canonical image name -> size in bytes
+	Nodes      map[string][]string             // Canonical image name -> names of nodes holding the image
+	digests    map[string]string               // Digest -> canonical image name
+	names      map[string]string               // Normalized reference -> canonical image name
+	tagDigests map[string]map[string][]string  // Normalized tagged reference -> digest -> names of nodes holding it
+	parsed     map[string]*util.ImageReference // Parsed node image names, nil for names that do not parse
+}
+
+// NewNodeImageIndex creates an empty node image index
+func NewNodeImageIndex() *NodeImageIndex {
+	return &NodeImageIndex{
+		Sizes:      make(map[string]int64),
+		Nodes:      make(map[string][]string),
+		digests:    make(map[string]string),
+		names:      make(map[string]string),
+		tagDigests: make(map[string]map[string][]string),
+		parsed:     make(map[string]*util.ImageReference),
+	}
+}
+
+// Add records a node image under its canonical name and indexes all of its names and digests
+func (ix *NodeImageIndex) Add(canonical string, names []string, size int64) {
+	ix.Sizes[canonical] = size
+
+	for _, name := range names {
+		if digest := util.ExtractDigest(name); digest != "" {
+			ix.digests[digest] = canonical
+		}
+		ix.names[util.NormalizeImageRef(name)] = canonical
+	}
+}
+
+// AddNode records that a node holds the image with the given canonical name
+func (ix *NodeImageIndex) AddNode(canonical, nodeName string) {
+	ix.Nodes[canonical] = append(ix.Nodes[canonical], nodeName)
+}
+
+// AddTagDigests records the digest a node holds for each tag of a node image.
+// A node image lists its tags and its repository digests among its names, and
+// each tag is paired with the digest of the same repository. Names are parsed
+// once, since most images are listed by many nodes.
+func (ix *NodeImageIndex) AddTagDigests(names []string, nodeName string) {
+	repoDigests := make(map[string]string)
+	var tags []*util.ImageReference
+	for _, name := range names {
+		ref := ix.parse(name)
+		if ref == nil {
+			continue
+		}
+		repo := ref.Registry + "/" + ref.Repository
+		if ref.Digest != "" {
+			repoDigests[repo] = ref.Digest
+		} else {
+			tags = append(tags, ref)
+		}
+	}
+
+	for _, tag := range tags {
+		digest, ok := repoDigests[tag.Registry+"/"+tag.Repository]
+		if !ok {
+			continue
+		}
+		name := tag.String()
+		if ix.tagDigests[name] == nil {
+			ix.tagDigests[name] = make(map[string][]string)
+		}
+		ix.tagDigests[name][digest] = append(ix.tagDigests[name][digest], nodeName)
+	}
+}
+
+// parse parses a node image name, reusing the result for names seen before
+func (ix *NodeImageIndex) parse(name string) *util.ImageReference {
+	if ref, ok := ix.parsed[name]; ok {
+		return ref
+	}
+	var ref *util.ImageReference
+	if parsed, err := util.ParseImageReference(name); err == nil {
+		ref = &parsed
+	}
+	ix.parsed[name] = ref
+	return ref
+}
+
+// Lookup finds the node image for a pod image reference and its optional
+// container status imageID. Digests are preferred over names because tags
+// are mutable and short names have many spellings.
+func (ix *NodeImageIndex) Lookup(ref, imageID string) (name string, size int64, found bool) {
+	for _, digest := range []string{util.ExtractDigest(imageID), util.ExtractDigest(ref)} {
+		if digest == "" {
+			continue
+		}
+		if canonical, ok := ix.digests[digest]; ok {
+			return canonical, ix.Sizes[canonical], true
+		}
+	}
+
+	if canonical, ok := ix.names[util.NormalizeImageRef(ref)]; ok {
+		return canonical, ix.Sizes[canonical], true
+	}
+
+	return "", 0, false
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeImageIndex_Lookup(t *testing.T) {
+	index := NewNodeImageIndex()
+	index.Add("docker.io/library/nginx:1.21", []string{
+		"docker.io/library/nginx@sha256:aaa111",
+		"docker.io/library/nginx:1.21",
+	}, 133000000)
+	index.Add("redis:6.2", []string{"redis:6.2"}, 110000000)
+
+	tests := []struct {
+		name     string
+		ref      string
+		imageID  string
+		wantName string
+		wantSize int64
+		wantOK   bool
+	}{
+		{
+			name:     "exact name",
+			ref:      "redis:6.2",
+			wantName: "redis:6.2",
+			wantSize: 110000000,
+			wantOK:   true,
+		},
+		{
+			name:     "short name normalized",
+			ref:      "nginx:1.21",
+			wantName: "docker.io/library/nginx:1.21",
+			wantSize: 133000000,
+			wantOK:   true,
+		},
+		{
+			name:     "digest from imageID wins over tag",
+			ref:      "nginx:stable",
+			imageID:  "docker-pullable://nginx@sha256:aaa111",
+			wantName: "docker.io/library/nginx:1.21",
+			wantSize: 133000000,
+			wantOK:   true,
+		},
+		{
+			name:     "digest pinned reference",
+			ref:      "nginx@sha256:aaa111",
+			wantName: "docker.io/library/nginx:1.21",
+			wantSize: 133000000,
+			wantOK:   true,
+		},
+		{
+			name:   "unknown image",
+			ref:    "custom:latest",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, size, ok := index.Lookup(tt.ref, tt.imageID)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantSize, size)
+		})
+	}
+}
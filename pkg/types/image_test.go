@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -122,13 +123,16 @@ func TestNewInaccessibleImage(t *testing.T) {
 		imageName string
 		wantName  string
 		wantReg   string
+		wantRepo  string
 		wantTag   string
+		wantDig   string
 	}{
 		{
 			name:      "private registry image",
 			imageName: "private.registry.com/app:v1.0",
 			wantName:  "private.registry.com/app:v1.0",
 			wantReg:   "private.registry.com",
+			wantRepo:  "app",
 			wantTag:   "v1.0",
 		},
 		{
@@ -136,8 +140,17 @@ func TestNewInaccessibleImage(t *testing.T) {
 			imageName: "nginx:latest",
 			wantName:  "nginx:latest",
 			wantReg:   "docker.io",
+			wantRepo:  "library/nginx",
 			wantTag:   "latest",
 		},
+		{
+			name:      "digest only image",
+			imageName: "registry:443/team/app@sha256:" + strings.Repeat("b", 64),
+			wantName:  "registry:443/team/app@sha256:" + strings.Repeat("b", 64),
+			wantReg:   "registry:443",
+			wantRepo:  "team/app",
+			wantDig:   "sha256:" + strings.Repeat("b", 64),
+		},
 	}
 
 	for _, tt := range tests {
@@ -145,9 +158,38 @@ func TestNewInaccessibleImage(t *testing.T) {
 			img := NewInaccessibleImage(tt.imageName)
 			assert.Equal(t, tt.wantName, img.Name)
 			assert.Equal(t, tt.wantReg, img.Registry)
+			assert.Equal(t, tt.wantRepo, img.Repository)
 			assert.Equal(t, tt.wantTag, img.Tag)
+			assert.Equal(t, tt.wantDig, img.Digest)
 			assert.True(t, img.Inaccessible)
 			assert.Equal(t, int64(0), img.Size)
 		})
 	}
 }
+
+func TestImage_SetNodes(t *testing.T) {
+	img := NewImage("nginx:1.21", 100000000)
+	img.SetNodes([]string{"node3", "node1", "node2", "node1"})
+
+	assert.Equal(t, []string{"node1", "node2", "node3"}, img.Nodes)
+	assert.Equal(t, 3, img.NodeCount)
+	assert.Equal(t, int64(300000000), img.ClusterSize)
+}
+
+func TestGetTopImagesByClusterSize(t *testing.T) {
+	analysis := &ImageAnalysis{
+		Images: []Image{
+			{Name: "big-on-one-node", Size: 5000000000, NodeCount: 1, ClusterSize: 5000000000},
+			{Name: "medium-everywhere", Size: 2000000000, NodeCount: 300, ClusterSize: 600000000000},
+			{Name: "small-on-some", Size: 100000000, NodeCount: 10, ClusterSize: 1000000000},
+		},
+	}
+
+	result := analysis.GetTopImagesByClusterSize(2)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "medium-everywhere", result[0].Name)
+	assert.Equal(t, "big-on-one-node", result[1].Name)
+
+	// Original order is preserved
+	assert.Equal(t, "big-on-one-node", analysis.Images[0].Name)
+}
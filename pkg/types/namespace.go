@@ -0,0 +1,55 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamespaceFilter selects the namespaces whose pods are analyzed
+type NamespaceFilter struct {
+	Include  []string // Namespaces to analyze; empty analyzes all namespaces
+	Exclude  []string // Namespaces to leave out
+	Selector string   // Label selector the namespaces must match; resolved into Include before listing pods
+}
+
+// IsAll reports whether the filter selects every namespace
+func (f NamespaceFilter) IsAll() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0 && f.Selector == ""
+}
+
+// Matches reports whether the namespace is included and not excluded. The
+// selector is not checked; it must be resolved into Include first.
+func (f NamespaceFilter) Matches(namespace string) bool {
+	for _, excluded := range f.Exclude {
+		if namespace == excluded {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, included := range f.Include {
+		if namespace == included {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a description of the selected namespaces, e.g.
+// "team-a, team-b" or "All (excluding kube-system)"
+func (f NamespaceFilter) String() string {
+	var b strings.Builder
+	if len(f.Include) == 0 {
+		b.WriteString("All")
+	} else {
+		b.WriteString(strings.Join(f.Include, ", "))
+	}
+	if f.Selector != "" {
+		fmt.Fprintf(&b, " matching %s", f.Selector)
+	}
+	if len(f.Exclude) > 0 {
+		fmt.Fprintf(&b, " (excluding %s)", strings.Join(f.Exclude, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      NamespaceFilter
+		expectAll   bool
+		expectMatch map[string]bool
+		expectText  string
+	}{
+		{
+			name:        "all namespaces",
+			filter:      NamespaceFilter{},
+			expectAll:   true,
+			expectMatch: map[string]bool{"default": true, "kube-system": true},
+			expectText:  "All",
+		},
+		{
+			name:        "included namespaces",
+			filter:      NamespaceFilter{Include: []string{"team-a", "team-b"}},
+			expectMatch: map[string]bool{"team-a": true, "team-b": true, "default": false},
+			expectText:  "team-a, team-b",
+		},
+		{
+			name:        "excluded namespaces",
+			filter:      NamespaceFilter{Exclude: []string{"kube-system"}},
+			expectMatch: map[string]bool{"default": true, "kube-system": false},
+			expectText:  "All (excluding kube-system)",
+		},
+		{
+			name:        "exclusion wins over inclusion",
+			filter:      NamespaceFilter{Include: []string{"team-a", "kube-system"}, Exclude: []string{"kube-system"}},
+			expectMatch: map[string]bool{"team-a": true, "kube-system": false},
+			expectText:  "team-a, kube-system (excluding kube-system)",
+		},
+		{
+			name:       "selector",
+			filter:     NamespaceFilter{Selector: "team=payments"},
+			expectText: "All matching team=payments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectAll, tt.filter.IsAll())
+			for namespace, expected := range tt.expectMatch {
+				assert.Equal(t, expected, tt.filter.Matches(namespace), namespace)
+			}
+			assert.Equal(t, tt.expectText, tt.filter.String())
+		})
+	}
+}
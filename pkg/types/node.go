@@ -0,0 +1,264 @@
+package types
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Node represents a simplified node structure for analysis
+type Node struct {
+	Name                        string
+	Images                      []NodeImage
+	EphemeralStorageCapacity    int64
+	EphemeralStorageAllocatable int64
+}
+
+// NodeImage represents an image cached on a node
+type NodeImage struct {
+	Name  string   // Canonical display name, defaults to the first reported name
+	Names []string // All names and digests the runtime reports for the image
+	Size  int64
+}
+
+// FromK8sNode converts a Kubernetes node to our internal Node type
+func FromK8sNode(k8sNode *corev1.Node) Node {
+	node := Node{
+		Name:   k8sNode.Name,
+		Images: make([]NodeImage, 0, len(k8sNode.Status.Images)),
+	}
+
+	if capacity, ok := k8sNode.Status.Capacity[corev1.ResourceEphemeralStorage]; ok {
+		node.EphemeralStorageCapacity = capacity.Value()
+	}
+	if allocatable, ok := k8sNode.Status.Allocatable[corev1.ResourceEphemeralStorage]; ok {
+		node.EphemeralStorageAllocatable = allocatable.Value()
+	}
+
+	for _, image := range k8sNode.Status.Images {
+		if len(image.Names) > 0 {
+			node.Images = append(node.Images, NodeImage{
+				Name:  image.Names[0],
+				Names: image.Names,
+				Size:  image.SizeBytes,
+			})
+		}
+	}
+
+	return node
+}
+
+// NodeUsage holds the image disk footprint of a single node
+type NodeUsage struct {
+	Name                        string `json:"name"`
+	ImageCount                  int    `json:"imageCount"`
+	TotalBytes                  int64  `json:"totalBytes"`
+	UnreferencedBytes           int64  `json:"unreferencedBytes"` // Bytes of images no pod scheduled to the node references
+	EphemeralStorageCapacity    int64  `json:"ephemeralStorageCapacity"`
+	EphemeralStorageAllocatable int64  `json:"ephemeralStorageAllocatable"`
+}
+
+// AllocatablePercent returns image bytes as a percentage of allocatable ephemeral
+// storage, or 0 if the node does not report allocatable ephemeral storage
+func (nu NodeUsage) AllocatablePercent() float64 {
+	if nu.EphemeralStorageAllocatable <= 0 {
+		return 0
+	}
+	return float64(nu.TotalBytes) / float64(nu.EphemeralStorageAllocatable) * 100
+}
+
+// AnalyzeNodeUsage computes the image footprint of each node. An image counts as
+// referenced when a pod scheduled to the node uses it, matched by digest or
+// normalized name (see NodeImageIndex). Results are sorted by total bytes (descending).
+func AnalyzeNodeUsage(nodes []Node, pods []Pod) []NodeUsage {
+	podsByNode := groupPodsByNode(pods)
+
+	usage := make([]NodeUsage, 0, len(nodes))
+	for _, node := range nodes {
+		nu := NodeUsage{
+			Name:                        node.Name,
+			ImageCount:                  len(node.Images),
+			EphemeralStorageCapacity:    node.EphemeralStorageCapacity,
+			EphemeralStorageAllocatable: node.EphemeralStorageAllocatable,
+		}
+
+		for _, image := range node.Images {
+			nu.TotalBytes += image.Size
+		}
+
+		referenced := node.referencedImages(uniqueImageRefs(podsByNode[node.Name]))
+		for _, image := range node.Images {
+			if !referenced[image.Name] {
+				nu.UnreferencedBytes += image.Size
+			}
+		}
+
+		usage = append(usage, nu)
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].TotalBytes != usage[j].TotalBytes {
+			return usage[i].TotalBytes > usage[j].TotalBytes
+		}
+		return usage[i].Name < usage[j].Name
+	})
+
+	return usage
+}
+
+// NodeAnalysis represents the per-node image footprint analysis results
+type NodeAnalysis struct {
+	Nodes       []NodeUsage
+	Performance *PerformanceMetrics
+}
+
+// TotalBytes returns the image bytes cached across all nodes
+func (na *NodeAnalysis) TotalBytes() int64 {
+	var total int64
+	for _, node := range na.Nodes {
+		total += node.TotalBytes
+	}
+	return total
+}
+
+// UnreferencedBytes returns the unreferenced image bytes across all nodes
+func (na *NodeAnalysis) UnreferencedBytes() int64 {
+	var total int64
+	for _, node := range na.Nodes {
+		total += node.UnreferencedBytes
+	}
+	return total
+}
+
+// UnusedImage represents an image cached on a node that no pod references
+type UnusedImage struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// NodeUnusedImages holds the unused images cached on a single node
+type NodeUnusedImages struct {
+	Node             string        `json:"node"`
+	Images           []UnusedImage `json:"images"`
+	ReclaimableBytes int64         `json:"reclaimableBytes"`
+}
+
+// UnusedImageAnalysis represents the unused image analysis results
+type UnusedImageAnalysis struct {
+	Nodes       []NodeUnusedImages
+	ClusterWide bool // True if images referenced by pods on any node count as used
+	Performance *PerformanceMetrics
+}
+
+// ReclaimableBytes returns the bytes of unused images across all nodes
+func (ua *UnusedImageAnalysis) ReclaimableBytes() int64 {
+	var total int64
+	for _, node := range ua.Nodes {
+		total += node.ReclaimableBytes
+	}
+	return total
+}
+
+// ImageCount returns the number of unused images across all nodes
+func (ua *UnusedImageAnalysis) ImageCount() int {
+	var count int
+	for _, node := range ua.Nodes {
+		count += len(node.Images)
+	}
+	return count
+}
+
+// FindUnusedImages finds images cached on each node that no pod scheduled to that
+// node references. With clusterWide, an image only counts as unused if no pod in the
+// cluster references it. Nodes without unused images are omitted; results are sorted
+// by reclaimable bytes (descending) and images within a node by size (descending).
+func FindUnusedImages(nodes []Node, pods []Pod, clusterWide bool) []NodeUnusedImages {
+	podsByNode := groupPodsByNode(pods)
+
+	var clusterRefs map[podImageRef]bool
+	if clusterWide {
+		clusterRefs = uniqueImageRefs(pods)
+	}
+
+	result := make([]NodeUnusedImages, 0, len(nodes))
+	for _, node := range nodes {
+		refs := clusterRefs
+		if !clusterWide {
+			refs = uniqueImageRefs(podsByNode[node.Name])
+		}
+		referenced := node.referencedImages(refs)
+
+		unused := NodeUnusedImages{Node: node.Name}
+		for _, image := range node.Images {
+			if !referenced[image.Name] {
+				unused.Images = append(unused.Images, UnusedImage{Name: image.Name, Size: image.Size})
+				unused.ReclaimableBytes += image.Size
+			}
+		}
+		if len(unused.Images) == 0 {
+			continue
+		}
+
+		sort.Slice(unused.Images, func(i, j int) bool {
+			if unused.Images[i].Size != unused.Images[j].Size {
+				return unused.Images[i].Size > unused.Images[j].Size
+			}
+			return unused.Images[i].Name < unused.Images[j].Name
+		})
+		result = append(result, unused)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ReclaimableBytes != result[j].ReclaimableBytes {
+			return result[i].ReclaimableBytes > result[j].ReclaimableBytes
+		}
+		return result[i].Node < result[j].Node
+	})
+
+	return result
+}
+
+// podImageRef is a pod image reference paired with its resolved container status imageID
+type podImageRef struct {
+	ref     string
+	imageID string
+}
+
+// uniqueImageRefs returns the distinct image references used by the pods, so that
+// matching against node images is done once per reference rather than once per pod
+func uniqueImageRefs(pods []Pod) map[podImageRef]bool {
+	refs := make(map[podImageRef]bool)
+	for _, pod := range pods {
+		for _, ref := range pod.Images {
+			refs[podImageRef{ref: ref, imageID: pod.ImageIDs[ref]}] = true
+		}
+	}
+	return refs
+}
+
+// groupPodsByNode groups pods by the node they are scheduled to, skipping unscheduled pods
+func groupPodsByNode(pods []Pod) map[string][]Pod {
+	podsByNode := make(map[string][]Pod)
+	for _, pod := range pods {
+		if pod.NodeName != "" {
+			podsByNode[pod.NodeName] = append(podsByNode[pod.NodeName], pod)
+		}
+	}
+	return podsByNode
+}
+
+// referencedImages returns the names of the node's images matched by any of the references
+func (n Node) referencedImages(refs map[podImageRef]bool) map[string]bool {
+	index := NewNodeImageIndex()
+	for _, image := range n.Images {
+		index.Add(image.Name, image.Names, image.Size)
+	}
+
+	referenced := make(map[string]bool)
+	for ref := range refs {
+		if name, _, found := index.Lookup(ref.ref, ref.imageID); found {
+			referenced[name] = true
+		}
+	}
+	return referenced
+}
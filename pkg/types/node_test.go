@@ -0,0 +1,138 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFromK8sNode(t *testing.T) {
+	k8sNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("100Gi"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("90Gi"),
+			},
+			Images: []corev1.ContainerImage{
+				{Names: []string{"nginx:1.21"}, SizeBytes: 133000000},
+				{Names: []string{}, SizeBytes: 1000}, // unnamed images are skipped
+			},
+		},
+	}
+
+	node := FromK8sNode(k8sNode)
+
+	assert.Equal(t, "node1", node.Name)
+	assert.Equal(t, int64(100*1024*1024*1024), node.EphemeralStorageCapacity)
+	assert.Equal(t, int64(90*1024*1024*1024), node.EphemeralStorageAllocatable)
+	assert.Equal(t, []NodeImage{{Name: "nginx:1.21", Names: []string{"nginx:1.21"}, Size: 133000000}}, node.Images)
+}
+
+func TestAnalyzeNodeUsage(t *testing.T) {
+	nodes := []Node{
+		{
+			Name: "node1",
+			Images: []NodeImage{
+				{Name: "docker.io/library/nginx:1.21", Names: []string{"docker.io/library/nginx:1.21"}, Size: 100000000},
+				{Name: "old-app:v1", Names: []string{"old-app:v1"}, Size: 300000000},
+			},
+			EphemeralStorageAllocatable: 1000000000,
+		},
+		{
+			Name: "node2",
+			Images: []NodeImage{
+				{Name: "redis:6.2", Names: []string{"redis:6.2"}, Size: 50000000},
+			},
+		},
+	}
+	pods := []Pod{
+		{Name: "web", Namespace: "default", NodeName: "node1", Images: []string{"nginx:1.21"}},
+		{Name: "pending", Namespace: "default", Images: []string{"old-app:v1"}},                 // unscheduled
+		{Name: "cache", Namespace: "default", NodeName: "node1", Images: []string{"redis:6.2"}}, // not on node2
+	}
+
+	usage := AnalyzeNodeUsage(nodes, pods)
+
+	assert.Equal(t, []NodeUsage{
+		{
+			Name:                        "node1",
+			ImageCount:                  2,
+			TotalBytes:                  400000000,
+			UnreferencedBytes:           300000000,
+			EphemeralStorageAllocatable: 1000000000,
+		},
+		{
+			Name:              "node2",
+			ImageCount:        1,
+			TotalBytes:        50000000,
+			UnreferencedBytes: 50000000,
+		},
+	}, usage)
+	assert.InDelta(t, 40.0, usage[0].AllocatablePercent(), 0.001)
+	assert.Equal(t, 0.0, usage[1].AllocatablePercent())
+
+	analysis := &NodeAnalysis{Nodes: usage}
+	assert.Equal(t, int64(450000000), analysis.TotalBytes())
+	assert.Equal(t, int64(350000000), analysis.UnreferencedBytes())
+}
+
+func TestFindUnusedImages(t *testing.T) {
+	nodes := []Node{
+		{
+			Name: "node1",
+			Images: []NodeImage{
+				{Name: "nginx:1.21", Names: []string{"nginx:1.21"}, Size: 100000000},
+				{Name: "redis:6.2", Names: []string{"redis:6.2"}, Size: 50000000},
+				{Name: "old-app:v1", Names: []string{"old-app:v1"}, Size: 300000000},
+			},
+		},
+		{
+			Name: "node2",
+			Images: []NodeImage{
+				{Name: "redis:6.2", Names: []string{"redis:6.2"}, Size: 50000000},
+			},
+		},
+	}
+	pods := []Pod{
+		{Name: "web", Namespace: "default", NodeName: "node1", Images: []string{"nginx:1.21"}},
+		{Name: "cache", Namespace: "default", NodeName: "node2", Images: []string{"redis:6.2"}},
+	}
+
+	t.Run("per node", func(t *testing.T) {
+		unused := FindUnusedImages(nodes, pods, false)
+
+		assert.Equal(t, []NodeUnusedImages{
+			{
+				Node: "node1",
+				Images: []UnusedImage{
+					{Name: "old-app:v1", Size: 300000000},
+					{Name: "redis:6.2", Size: 50000000},
+				},
+				ReclaimableBytes: 350000000,
+			},
+		}, unused)
+
+		analysis := &UnusedImageAnalysis{Nodes: unused}
+		assert.Equal(t, int64(350000000), analysis.ReclaimableBytes())
+		assert.Equal(t, 2, analysis.ImageCount())
+	})
+
+	t.Run("cluster wide", func(t *testing.T) {
+		// redis is used on node2, so it is not unused on node1 either
+		unused := FindUnusedImages(nodes, pods, true)
+
+		assert.Equal(t, []NodeUnusedImages{
+			{
+				Node:             "node1",
+				Images:           []UnusedImage{{Name: "old-app:v1", Size: 300000000}},
+				ReclaimableBytes: 300000000,
+			},
+		}, unused)
+	})
+}
@@ -2,13 +2,71 @@ package types
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Container types, distinguishing how a container runs within its pod
+const (
+	ContainerTypeRegular   = "regular"
+	ContainerTypeInit      = "init"
+	ContainerTypeSidecar   = "sidecar"   // Init container with restartPolicy Always, running alongside regular containers
+	ContainerTypeEphemeral = "ephemeral" // Added to a running pod, e.g. by kubectl debug
+)
+
+// ContainerTypes lists every container type
+var ContainerTypes = []string{ContainerTypeRegular, ContainerTypeInit, ContainerTypeSidecar, ContainerTypeEphemeral}
+
 // Pod represents a simplified pod structure for analysis
 type Pod struct {
-	Name      string
-	Namespace string
-	Images    []string
+	Name       string
+	Namespace  string
+	NodeName   string
+	Phase      string
+	Owner      *Workload // Controlling owner reference, nil for standalone pods
+	Images     []string
+	ImageIDs   map[string]string // Maps a spec image reference to the imageID reported in container status
+	Containers []PodContainer    // Containers in spec order: regular, then init and sidecar, then ephemeral
+}
+
+// PodContainer is a container of a pod and the image it runs
+type PodContainer struct {
+	Name  string
+	Image string
+	Type  string // ContainerType* value
+}
+
+// Workload identifies a Kubernetes object that owns pods
+type Workload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// String returns the workload in "Kind/name" form
+func (w Workload) String() string {
+	return w.Kind + "/" + w.Name
+}
+
+// ResolveWorkload returns the top-level workload controlling the pod. owners maps
+// intermediate controllers to their own controller, which is how ReplicaSets are
+// resolved to Deployments and Jobs to CronJobs. Standalone pods are their own workload.
+func (p Pod) ResolveWorkload(owners map[Workload]Workload) Workload {
+	if p.Owner == nil {
+		return Workload{Kind: "Pod", Namespace: p.Namespace, Name: p.Name}
+	}
+	if parent, ok := owners[*p.Owner]; ok {
+		return parent
+	}
+	return *p.Owner
+}
+
+// ControllerOf returns the controlling owner of an object as a Workload, or nil if it has none
+func ControllerOf(obj metav1.Object) *Workload {
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		return nil
+	}
+	return &Workload{Kind: ref.Kind, Namespace: obj.GetNamespace(), Name: ref.Name}
 }
 
 // PodList represents a collection of pods
@@ -19,24 +77,83 @@ type PodList struct {
 // FromK8sPod converts a Kubernetes pod to our internal Pod type
 func FromK8sPod(k8sPod *corev1.Pod) Pod {
 	pod := Pod{
-		Name:      k8sPod.Name,
-		Namespace: k8sPod.Namespace,
-		Images:    make([]string, 0),
+		Name:       k8sPod.Name,
+		Namespace:  k8sPod.Namespace,
+		NodeName:   k8sPod.Spec.NodeName,
+		Phase:      string(k8sPod.Status.Phase),
+		Owner:      ControllerOf(k8sPod),
+		Images:     make([]string, 0),
+		ImageIDs:   make(map[string]string),
+		Containers: make([]PodContainer, 0),
+	}
+
+	// Index resolved image IDs by container name so they can be matched to spec images
+	statusImageIDs := make(map[string]string)
+	for _, status := range k8sPod.Status.ContainerStatuses {
+		statusImageIDs[status.Name] = status.ImageID
+	}
+	for _, status := range k8sPod.Status.InitContainerStatuses {
+		statusImageIDs[status.Name] = status.ImageID
+	}
+	for _, status := range k8sPod.Status.EphemeralContainerStatuses {
+		statusImageIDs[status.Name] = status.ImageID
+	}
+
+	addContainer := func(name, image, containerType string) {
+		if image == "" {
+			return
+		}
+		pod.Images = append(pod.Images, image)
+		pod.Containers = append(pod.Containers, PodContainer{Name: name, Image: image, Type: containerType})
+		if imageID := statusImageIDs[name]; imageID != "" {
+			pod.ImageIDs[image] = imageID
+		}
 	}
 
 	// Extract container images
 	for _, container := range k8sPod.Spec.Containers {
-		if container.Image != "" {
-			pod.Images = append(pod.Images, container.Image)
-		}
+		addContainer(container.Name, container.Image, ContainerTypeRegular)
 	}
 
-	// Extract init container images
+	// Extract init container images. Init containers that always restart run
+	// alongside the regular containers as sidecars.
 	for _, container := range k8sPod.Spec.InitContainers {
-		if container.Image != "" {
-			pod.Images = append(pod.Images, container.Image)
+		containerType := ContainerTypeInit
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			containerType = ContainerTypeSidecar
 		}
+		addContainer(container.Name, container.Image, containerType)
+	}
+
+	// Extract ephemeral container images, such as those added by kubectl debug
+	for _, container := range k8sPod.Spec.EphemeralContainers {
+		addContainer(container.Name, container.Image, ContainerTypeEphemeral)
 	}
 
 	return pod
 }
+
+// WithContainerTypes returns a copy of the pod holding only the containers of
+// the given types, and their images
+func (p Pod) WithContainerTypes(containerTypes []string) Pod {
+	allowed := make(map[string]bool, len(containerTypes))
+	for _, t := range containerTypes {
+		allowed[t] = true
+	}
+
+	filtered := p
+	filtered.Images = make([]string, 0, len(p.Images))
+	filtered.ImageIDs = make(map[string]string)
+	filtered.Containers = make([]PodContainer, 0, len(p.Containers))
+	for _, c := range p.Containers {
+		if !allowed[c.Type] {
+			continue
+		}
+		filtered.Containers = append(filtered.Containers, c)
+		filtered.Images = append(filtered.Images, c.Image)
+		if imageID, ok := p.ImageIDs[c.Image]; ok {
+			filtered.ImageIDs[c.Image] = imageID
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFromK8sPod(t *testing.T) {
+	controller := true
+	k8sPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-abc12",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "ReplicaSet", Name: "web-5d4f8", Controller: &controller},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:       "node1",
+			Containers:     []corev1.Container{{Name: "app", Image: "nginx:1.21"}},
+			InitContainers: []corev1.Container{{Name: "init", Image: "busybox"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", ImageID: "docker-pullable://nginx@sha256:abc123"},
+			},
+		},
+	}
+
+	pod := FromK8sPod(k8sPod)
+
+	assert.Equal(t, "web-abc12", pod.Name)
+	assert.Equal(t, "default", pod.Namespace)
+	assert.Equal(t, "node1", pod.NodeName)
+	assert.Equal(t, []string{"nginx:1.21", "busybox"}, pod.Images)
+	assert.Equal(t, map[string]string{"nginx:1.21": "docker-pullable://nginx@sha256:abc123"}, pod.ImageIDs)
+	assert.Equal(t, &Workload{Kind: "ReplicaSet", Namespace: "default", Name: "web-5d4f8"}, pod.Owner)
+}
+
+func TestFromK8sPod_ContainerTypes(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	k8sPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx:1.21"}},
+			InitContainers: []corev1.Container{
+				{Name: "migrate", Image: "app:v1"},
+				{Name: "proxy", Image: "envoy:v1", RestartPolicy: &always},
+			},
+			EphemeralContainers: []corev1.EphemeralContainer{
+				{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger-x1", Image: "busybox"}},
+			},
+		},
+		Status: corev1.PodStatus{
+			EphemeralContainerStatuses: []corev1.ContainerStatus{
+				{Name: "debugger-x1", ImageID: "docker.io/library/busybox@sha256:abc123"},
+			},
+		},
+	}
+
+	pod := FromK8sPod(k8sPod)
+
+	assert.Equal(t, []string{"nginx:1.21", "app:v1", "envoy:v1", "busybox"}, pod.Images)
+	assert.Equal(t, []PodContainer{
+		{Name: "app", Image: "nginx:1.21", Type: ContainerTypeRegular},
+		{Name: "migrate", Image: "app:v1", Type: ContainerTypeInit},
+		{Name: "proxy", Image: "envoy:v1", Type: ContainerTypeSidecar},
+		{Name: "debugger-x1", Image: "busybox", Type: ContainerTypeEphemeral},
+	}, pod.Containers)
+	assert.Equal(t, "docker.io/library/busybox@sha256:abc123", pod.ImageIDs["busybox"])
+
+	filtered := pod.WithContainerTypes([]string{ContainerTypeEphemeral, ContainerTypeSidecar})
+	assert.Equal(t, []string{"envoy:v1", "busybox"}, filtered.Images)
+	assert.Equal(t, map[string]string{"busybox": "docker.io/library/busybox@sha256:abc123"}, filtered.ImageIDs)
+	assert.Len(t, pod.Images, 4, "filtering must not modify the original pod")
+}
+
+func TestPod_ResolveWorkload(t *testing.T) {
+	owners := map[Workload]Workload{
+		{Kind: "ReplicaSet", Namespace: "default", Name: "web-5d4f8"}: {Kind: "Deployment", Namespace: "default", Name: "web"},
+		{Kind: "Job", Namespace: "default", Name: "backup-28000"}:     {Kind: "CronJob", Namespace: "default", Name: "backup"},
+	}
+
+	tests := []struct {
+		name string
+		pod  Pod
+		want Workload
+	}{
+		{
+			name: "replica set resolves to deployment",
+			pod:  Pod{Name: "web-abc12", Namespace: "default", Owner: &Workload{Kind: "ReplicaSet", Namespace: "default", Name: "web-5d4f8"}},
+			want: Workload{Kind: "Deployment", Namespace: "default", Name: "web"},
+		},
+		{
+			name: "job resolves to cronjob",
+			pod:  Pod{Name: "backup-28000-xyz", Namespace: "default", Owner: &Workload{Kind: "Job", Namespace: "default", Name: "backup-28000"}},
+			want: Workload{Kind: "CronJob", Namespace: "default", Name: "backup"},
+		},
+		{
+			name: "daemon set is top level",
+			pod:  Pod{Name: "agent-x", Namespace: "kube-system", Owner: &Workload{Kind: "DaemonSet", Namespace: "kube-system", Name: "agent"}},
+			want: Workload{Kind: "DaemonSet", Namespace: "kube-system", Name: "agent"},
+		},
+		{
+			name: "orphaned replica set stays as is",
+			pod:  Pod{Name: "rs-pod", Namespace: "default", Owner: &Workload{Kind: "ReplicaSet", Namespace: "default", Name: "standalone"}},
+			want: Workload{Kind: "ReplicaSet", Namespace: "default", Name: "standalone"},
+		},
+		{
+			name: "standalone pod",
+			pod:  Pod{Name: "debug", Namespace: "default"},
+			want: Workload{Kind: "Pod", Namespace: "default", Name: "debug"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.pod.ResolveWorkload(owners))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/ronaknnathani/kubectl-analyze-images/pkg/util"
+)
+
+// Policy rules reported in violations
+const (
+	RuleMaxImageSize      = "max-image-size"
+	RuleNamespaceBudget   = "namespace-budget"
+	RuleLatestTag         = "latest-tag"
+	RuleAllowedRegistries = "allowed-registries"
+)
+
+// Policy holds the thresholds an analysis is checked against. Sizes are
+// Kubernetes quantities such as "2Gi" or "500M"; unset rules are not checked.
+type Policy struct {
+	MaxImageSize      *resource.Quantity           `json:"maxImageSize,omitempty"`
+	NamespaceBudget   *resource.Quantity           `json:"namespaceBudget,omitempty"`  // Budget for every namespace
+	NamespaceBudgets  map[string]resource.Quantity `json:"namespaceBudgets,omitempty"` // Per-namespace overrides of NamespaceBudget
+	DisallowLatestTag bool                         `json:"disallowLatestTag,omitempty"`
+	AllowedRegistries []string                     `json:"allowedRegistries,omitempty"` // Registries or registry/repository prefixes
+}
+
+// PolicyViolation describes an image or namespace that breaks a policy rule
+type PolicyViolation struct {
+	Rule    string `json:"rule"`
+	Subject string `json:"subject"` // Image name or namespace
+	Message string `json:"message"`
+}
+
+// IsEmpty reports whether the policy has no rules
+func (p *Policy) IsEmpty() bool {
+	return p.MaxImageSize == nil && !p.RequiresNamespaces() && !p.DisallowLatestTag && len(p.AllowedRegistries) == 0
+}
+
+// RequiresNamespaces reports whether the policy needs per-namespace attribution
+func (p *Policy) RequiresNamespaces() bool {
+	return p.NamespaceBudget != nil || len(p.NamespaceBudgets) > 0
+}
+
+// Evaluate checks the analysis against the policy and returns the violations,
+// sorted by rule and subject. Namespace budgets are checked against the total
+// bytes attributed to each namespace, so the analysis must attribute namespace
+// usage, and the latest tag rule needs the image references of the listed pods.
+func (p *Policy) Evaluate(analysis *ImageAnalysis) []PolicyViolation {
+	var violations []PolicyViolation
+
+	for _, img := range analysis.Images {
+		if p.MaxImageSize != nil && img.Size > p.MaxImageSize.Value() {
+			violations = append(violations, PolicyViolation{
+				Rule:    RuleMaxImageSize,
+				Subject: img.Name,
+				Message: fmt.Sprintf("image size %s exceeds maximum %s", util.FormatBytes(img.Size), p.MaxImageSize.String()),
+			})
+		}
+
+		if len(p.AllowedRegistries) > 0 && !p.registryAllowed(img) {
+			registry := img.Registry
+			if registry == "" {
+				registry = "unknown"
+			}
+			violations = append(violations, PolicyViolation{
+				Rule:    RuleAllowedRegistries,
+				Subject: img.Name,
+				Message: fmt.Sprintf("registry %s is not in the allow-list", registry),
+			})
+		}
+	}
+
+	// Tags are checked on the references in pod specs, since a node may list the
+	// image a pod pinned by digest or tag under another of its tags
+	if p.DisallowLatestTag && analysis.PodImages != nil {
+		for ref := range analysis.PodImages.Images {
+			if message := latestTagMessage(ref); message != "" {
+				violations = append(violations, PolicyViolation{
+					Rule:    RuleLatestTag,
+					Subject: ref,
+					Message: message,
+				})
+			}
+		}
+	}
+
+	for _, ns := range analysis.Namespaces {
+		budget := p.NamespaceBudget
+		if override, ok := p.NamespaceBudgets[ns.Namespace]; ok {
+			budget = &override
+		}
+		if budget != nil && ns.TotalBytes() > budget.Value() {
+			violations = append(violations, PolicyViolation{
+				Rule:    RuleNamespaceBudget,
+				Subject: ns.Namespace,
+				Message: fmt.Sprintf("namespace uses %s of images, over budget %s", util.FormatBytes(ns.TotalBytes()), budget.String()),
+			})
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Rule != violations[j].Rule {
+			return violations[i].Rule < violations[j].Rule
+		}
+		return violations[i].Subject < violations[j].Subject
+	})
+
+	return violations
+}
+
+// latestTagMessage returns why an image reference resolves to :latest, or an
+// empty string if it does not. References that cannot be parsed are skipped.
+func latestTagMessage(ref string) string {
+	if util.IsUntagged(ref) {
+		return "image has no tag, so it resolves to :latest"
+	}
+	parsed, err := util.ParseImageReference(ref)
+	if err == nil && parsed.Tag == util.DefaultTag && parsed.Digest == "" {
+		return "image uses the :latest tag"
+	}
+	return ""
+}
+
+// registryAllowed reports whether the image registry, or a registry/repository
+// prefix of the image, is in the allow-list
+func (p *Policy) registryAllowed(img Image) bool {
+	ref := img.Registry + "/" + img.Repository
+	for _, allowed := range p.AllowedRegistries {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if img.Registry == allowed || ref == allowed || strings.HasPrefix(ref, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// quantity parses a size for test policies
+func quantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	analysis := &ImageAnalysis{
+		Images: []Image{
+			*NewImage("gcr.io/team/app:v1", 3*1024*1024*1024),
+			*NewImage("nginx", 100*1024*1024),
+			*NewImage("quay.io/other/tool@sha256:0123456789012345678901234567890123456789012345678901234567890123", 10),
+		},
+		Namespaces: []NamespaceUsage{
+			{Namespace: "big", UniqueBytes: 30 * 1024 * 1024 * 1024},
+			{Namespace: "small", UniqueBytes: 1024},
+			{Namespace: "exempt", UniqueBytes: 30 * 1024 * 1024 * 1024},
+		},
+		// Tags are checked on the references in pod specs, not the node image names
+		PodImages: NewPodImages(Pod{Name: "web", Namespace: "default", Images: []string{
+			"gcr.io/team/app:v1",
+			"nginx",
+			"app:latest",
+			"registry.example.com/app@sha256:0123456789012345678901234567890123456789012345678901234567890123",
+		}}),
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []PolicyViolation
+	}{
+		{
+			name:   "empty policy",
+			policy: Policy{},
+			want:   nil,
+		},
+		{
+			name:   "max image size",
+			policy: Policy{MaxImageSize: quantity("2Gi")},
+			want: []PolicyViolation{
+				{Rule: RuleMaxImageSize, Subject: "gcr.io/team/app:v1", Message: "image size 3.0 GB exceeds maximum 2Gi"},
+			},
+		},
+		{
+			name:   "latest tag",
+			policy: Policy{DisallowLatestTag: true},
+			want: []PolicyViolation{
+				{Rule: RuleLatestTag, Subject: "app:latest", Message: "image uses the :latest tag"},
+				{Rule: RuleLatestTag, Subject: "nginx", Message: "image has no tag, so it resolves to :latest"},
+			},
+		},
+		{
+			name:   "registry allow-list with repository prefix",
+			policy: Policy{AllowedRegistries: []string{"docker.io", "gcr.io/team/"}},
+			want: []PolicyViolation{
+				{Rule: RuleAllowedRegistries, Subject: "quay.io/other/tool@sha256:0123456789012345678901234567890123456789012345678901234567890123", Message: "registry quay.io is not in the allow-list"},
+			},
+		},
+		{
+			name: "namespace budget with override",
+			policy: Policy{
+				NamespaceBudget:  quantity("20Gi"),
+				NamespaceBudgets: map[string]resource.Quantity{"exempt": resource.MustParse("50Gi")},
+			},
+			want: []PolicyViolation{
+				{Rule: RuleNamespaceBudget, Subject: "big", Message: "namespace uses 30.0 GB of images, over budget 20Gi"},
+			},
+		},
+		{
+			name:   "violations sorted by rule",
+			policy: Policy{MaxImageSize: quantity("2Gi"), DisallowLatestTag: true},
+			want: []PolicyViolation{
+				{Rule: RuleLatestTag, Subject: "app:latest", Message: "image uses the :latest tag"},
+				{Rule: RuleLatestTag, Subject: "nginx", Message: "image has no tag, so it resolves to :latest"},
+				{Rule: RuleMaxImageSize, Subject: "gcr.io/team/app:v1", Message: "image size 3.0 GB exceeds maximum 2Gi"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Evaluate(analysis))
+		})
+	}
+}
+
+func TestPolicy_IsEmpty(t *testing.T) {
+	assert.True(t, (&Policy{}).IsEmpty())
+	assert.False(t, (&Policy{DisallowLatestTag: true}).IsEmpty())
+	assert.False(t, (&Policy{NamespaceBudgets: map[string]resource.Quantity{"a": resource.MustParse("1Gi")}}).IsEmpty())
+
+	p := &Policy{NamespaceBudget: quantity("1Gi")}
+	require.False(t, p.IsEmpty())
+	assert.True(t, p.RequiresNamespaces())
+}
@@ -7,3 +7,16 @@ import "io"
 type Printer interface {
 	Print(w io.Writer, analysis *ImageAnalysis) error
 }
+
+// NodePrinter defines the interface for per-node report formatters.
+// Implementations write node and unused image analysis results to the provided writer.
+type NodePrinter interface {
+	PrintNodes(w io.Writer, analysis *NodeAnalysis) error
+	PrintUnusedImages(w io.Writer, analysis *UnusedImageAnalysis) error
+}
+
+// DiffPrinter defines the interface for image diff formatters.
+// Implementations write the differences between two analyses to the provided writer.
+type DiffPrinter interface {
+	PrintDiff(w io.Writer, diff *ImageDiff) error
+}
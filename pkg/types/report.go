@@ -0,0 +1,181 @@
+package types
+
+// ReportAPIVersion is the version of the JSON report schema. Fields may be added
+// within a version; renaming or removing a field requires a new version.
+const ReportAPIVersion = "analyze-images/v1"
+
+// Kinds of JSON reports
+const (
+	ImageReportKind       = "ImageReport"
+	NodeReportKind        = "NodeReport"
+	UnusedImageReportKind = "UnusedImageReport"
+	ImageDiffReportKind   = "ImageDiffReport"
+)
+
+// ReportHeader identifies the schema of a JSON report
+type ReportHeader struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+// ImageReport is the JSON schema of the image analysis report
+type ImageReport struct {
+	ReportHeader
+	Performance    *PerformanceMetrics  `json:"performance,omitempty"`
+	Summary        ImageReportSummary   `json:"summary"`
+	Images         []Image              `json:"images"`
+	Namespaces     []NamespaceUsage     `json:"namespaces,omitempty"`
+	Workloads      []WorkloadUsage      `json:"workloads,omitempty"`
+	Registries     []RegistryUsage      `json:"registries,omitempty"`
+	Repositories   []RegistryUsage      `json:"repositories,omitempty"`
+	ContainerTypes []ContainerTypeUsage `json:"containerTypes,omitempty"`
+	Violations     []PolicyViolation    `json:"violations,omitempty"`
+	TagHygiene     *TagHygiene          `json:"tagHygiene,omitempty"`
+	TagDrift       []TagDrift           `json:"tagDrift,omitempty"`
+}
+
+// ImageReportSummary holds the totals of an image analysis report
+type ImageReportSummary struct {
+	TotalImages int   `json:"totalImages"`
+	TotalSize   int64 `json:"totalSize"`
+	UniqueSize  int64 `json:"uniqueSize"`
+	ClusterSize int64 `json:"clusterSize"` // Bytes used across all nodes, counting every copy of an image
+}
+
+// NewImageReport builds the JSON report for an image analysis
+func NewImageReport(analysis *ImageAnalysis) *ImageReport {
+	report := &ImageReport{
+		ReportHeader: ReportHeader{APIVersion: ReportAPIVersion, Kind: ImageReportKind},
+		Performance:  analysis.Performance,
+		Summary: ImageReportSummary{
+			TotalImages: len(analysis.Images),
+			TotalSize:   analysis.TotalSize,
+			UniqueSize:  analysis.UniqueSize,
+			ClusterSize: analysis.ClusterSize,
+		},
+		Images:         analysis.Images,
+		Namespaces:     analysis.Namespaces,
+		Workloads:      analysis.Workloads,
+		Registries:     analysis.Registries,
+		Repositories:   analysis.Repositories,
+		ContainerTypes: analysis.ContainerTypes,
+		Violations:     analysis.Violations,
+		TagHygiene:     analysis.TagHygiene,
+		TagDrift:       analysis.TagDrift,
+	}
+
+	// Ensure an empty image list encodes as [] rather than null
+	if report.Images == nil {
+		report.Images = []Image{}
+	}
+
+	return report
+}
+
+// Analysis converts a decoded report back into an image analysis
+func (r *ImageReport) Analysis() *ImageAnalysis {
+	return &ImageAnalysis{
+		Images:         r.Images,
+		TotalSize:      r.Summary.TotalSize,
+		UniqueSize:     r.Summary.UniqueSize,
+		ClusterSize:    r.Summary.ClusterSize,
+		Performance:    r.Performance,
+		Namespaces:     r.Namespaces,
+		Workloads:      r.Workloads,
+		Registries:     r.Registries,
+		Repositories:   r.Repositories,
+		ContainerTypes: r.ContainerTypes,
+		Violations:     r.Violations,
+		TagHygiene:     r.TagHygiene,
+		TagDrift:       r.TagDrift,
+	}
+}
+
+// NodeReport is the JSON schema of the per-node image footprint report
+type NodeReport struct {
+	ReportHeader
+	Performance *PerformanceMetrics `json:"performance,omitempty"`
+	Summary     NodeReportSummary   `json:"summary"`
+	Nodes       []NodeUsage         `json:"nodes"`
+}
+
+// NodeReportSummary holds the totals of a per-node report
+type NodeReportSummary struct {
+	TotalNodes       int   `json:"totalNodes"`
+	TotalSize        int64 `json:"totalSize"`
+	UnreferencedSize int64 `json:"unreferencedSize"`
+}
+
+// NewNodeReport builds the JSON report for a node analysis
+func NewNodeReport(analysis *NodeAnalysis) *NodeReport {
+	report := &NodeReport{
+		ReportHeader: ReportHeader{APIVersion: ReportAPIVersion, Kind: NodeReportKind},
+		Performance:  analysis.Performance,
+		Summary: NodeReportSummary{
+			TotalNodes:       len(analysis.Nodes),
+			TotalSize:        analysis.TotalBytes(),
+			UnreferencedSize: analysis.UnreferencedBytes(),
+		},
+		Nodes: analysis.Nodes,
+	}
+
+	// Ensure an empty node list encodes as [] rather than null
+	if report.Nodes == nil {
+		report.Nodes = []NodeUsage{}
+	}
+
+	return report
+}
+
+// UnusedImageReport is the JSON schema of the unused image report
+type UnusedImageReport struct {
+	ReportHeader
+	Performance *PerformanceMetrics      `json:"performance,omitempty"`
+	Summary     UnusedImageReportSummary `json:"summary"`
+	Nodes       []NodeUnusedImages       `json:"nodes"`
+}
+
+// UnusedImageReportSummary holds the totals of an unused image report
+type UnusedImageReportSummary struct {
+	ClusterWide     bool  `json:"clusterWide"`
+	TotalNodes      int   `json:"totalNodes"`
+	TotalImages     int   `json:"totalImages"`
+	ReclaimableSize int64 `json:"reclaimableSize"`
+}
+
+// NewUnusedImageReport builds the JSON report for an unused image analysis
+func NewUnusedImageReport(analysis *UnusedImageAnalysis) *UnusedImageReport {
+	report := &UnusedImageReport{
+		ReportHeader: ReportHeader{APIVersion: ReportAPIVersion, Kind: UnusedImageReportKind},
+		Performance:  analysis.Performance,
+		Summary: UnusedImageReportSummary{
+			ClusterWide:     analysis.ClusterWide,
+			TotalNodes:      len(analysis.Nodes),
+			TotalImages:     analysis.ImageCount(),
+			ReclaimableSize: analysis.ReclaimableBytes(),
+		},
+		Nodes: analysis.Nodes,
+	}
+
+	// Ensure an empty node list encodes as [] rather than null
+	if report.Nodes == nil {
+		report.Nodes = []NodeUnusedImages{}
+	}
+
+	return report
+}
+
+// ImageDiffReport is the JSON schema of the diff between two image analyses. The
+// fields of the embedded header and diff are encoded at the top level.
+type ImageDiffReport struct {
+	ReportHeader
+	*ImageDiff
+}
+
+// NewImageDiffReport builds the JSON report for an image diff
+func NewImageDiffReport(diff *ImageDiff) *ImageDiffReport {
+	return &ImageDiffReport{
+		ReportHeader: ReportHeader{APIVersion: ReportAPIVersion, Kind: ImageDiffReportKind},
+		ImageDiff:    diff,
+	}
+}
@@ -31,3 +31,15 @@ func FormatBytesShort(bytes int64) string {
 	}
 	return fmt.Sprintf("%.0f%c", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
+
+// FormatBytesDelta formats a signed byte difference, e.g. "+1.5 GB" or "-200.0 MB"
+func FormatBytesDelta(bytes int64) string {
+	switch {
+	case bytes > 0:
+		return "+" + FormatBytes(bytes)
+	case bytes < 0:
+		return "-" + FormatBytes(-bytes)
+	default:
+		return FormatBytes(0)
+	}
+}
@@ -102,3 +102,22 @@ func TestFormatBytesShort(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatBytesDelta(t *testing.T) {
+	tests := []struct {
+		name     string
+		bytes    int64
+		expected string
+	}{
+		{name: "zero", bytes: 0, expected: "0 B"},
+		{name: "growth", bytes: 1536, expected: "+1.5 KB"},
+		{name: "shrink", bytes: -1073741824, expected: "-1.0 GB"},
+		{name: "small shrink", bytes: -500, expected: "-500 B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatBytesDelta(tt.bytes))
+		})
+	}
+}
@@ -1,30 +1,161 @@
 package util
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 )
 
-// ExtractRegistryAndTag extracts registry and tag from image name
-func ExtractRegistryAndTag(imageName string) (registry, tag string) {
-	parts := strings.Split(imageName, "/")
-	registry = "docker.io"
-	tag = "latest"
+const (
+	// DefaultRegistry is the registry assumed for references without a domain
+	DefaultRegistry = "docker.io"
+	// DefaultTag is the tag assumed for references without a tag or digest
+	DefaultTag = "latest"
 
-	// Get the last part which contains the image name and possibly tag
-	lastPart := parts[len(parts)-1]
+	legacyDefaultRegistry = "index.docker.io"
+	officialRepoPrefix    = "library/"
+)
+
+var (
+	// Grammar follows the distribution reference spec:
+	// https://github.com/distribution/reference/blob/main/reference.go
+	domainRegexp        = regexp.MustCompile(`^(?:\[[0-9A-Fa-f:]+\]|[A-Za-z0-9](?:[A-Za-z0-9.-]*[A-Za-z0-9])?)(?::[0-9]+)?$`)
+	pathComponentRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	tagRegexp           = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	digestRegexp        = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9A-Fa-f]{32,}$`)
+	// Bare digests are limited to the registered algorithms, so that a short name
+	// tagged with a commit hash (e.g. "app:<40 hex>") is not mistaken for one
+	bareDigestRegexp = regexp.MustCompile(`^(?:sha256:[0-9a-f]{64}|sha512:[0-9a-f]{128})$`)
+)
+
+// ImageReference holds the components of a parsed container image reference
+type ImageReference struct {
+	Registry   string // Registry host, including port if present (e.g. "localhost:5000")
+	Repository string // Repository path without registry (e.g. "library/nginx")
+	Tag        string // Tag, empty for digest-only references
+	Digest     string // Digest in "algorithm:hex" form, empty if not pinned
+}
+
+// String returns the fully qualified form of the reference. Digest-only
+// references have no repository and are returned as the bare digest.
+func (r ImageReference) String() string {
+	if r.Repository == "" {
+		return r.Digest
+	}
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// ParseImageReference parses an image reference into its registry, repository,
+// tag and digest. Docker Hub short names are normalized, so "ubuntu" becomes
+// registry "docker.io", repository "library/ubuntu" and tag "latest". A bare
+// digest such as "sha256:<hex>" only sets the digest.
+func ParseImageReference(ref string) (ImageReference, error) {
+	if ref == "" {
+		return ImageReference{}, fmt.Errorf("invalid image reference: empty")
+	}
+
+	// A bare digest would otherwise parse as repository "sha256" with the hex as tag
+	if bareDigestRegexp.MatchString(ref) {
+		return ImageReference{Digest: ref}, nil
+	}
+
+	var parsed ImageReference
+	remainder := ref
+
+	// Split off the digest
+	if i := strings.Index(remainder, "@"); i >= 0 {
+		parsed.Digest = remainder[i+1:]
+		remainder = remainder[:i]
+		if !digestRegexp.MatchString(parsed.Digest) {
+			return ImageReference{}, fmt.Errorf("invalid image reference %q: malformed digest", ref)
+		}
+	}
+
+	// Split off the tag, which follows the last ':' after the last '/' so that
+	// registry ports are not mistaken for tags
+	if i := strings.LastIndex(remainder, ":"); i > strings.LastIndex(remainder, "/") {
+		parsed.Tag = remainder[i+1:]
+		remainder = remainder[:i]
+		if !tagRegexp.MatchString(parsed.Tag) {
+			return ImageReference{}, fmt.Errorf("invalid image reference %q: malformed tag", ref)
+		}
+	}
+
+	// The first path component is a registry only if it looks like a host
+	parsed.Registry = DefaultRegistry
+	parsed.Repository = remainder
+	if i := strings.Index(remainder, "/"); i >= 0 {
+		first := remainder[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" || strings.ToLower(first) != first {
+			if !domainRegexp.MatchString(first) {
+				return ImageReference{}, fmt.Errorf("invalid image reference %q: malformed registry", ref)
+			}
+			parsed.Registry = first
+			parsed.Repository = remainder[i+1:]
+		}
+	}
+	if parsed.Registry == legacyDefaultRegistry {
+		parsed.Registry = DefaultRegistry
+	}
 
-	// Extract tag from the last part if present
-	if strings.Contains(lastPart, ":") {
-		tagParts := strings.Split(lastPart, ":")
-		if len(tagParts) >= 2 {
-			tag = tagParts[1]
+	for _, component := range strings.Split(parsed.Repository, "/") {
+		if !pathComponentRegexp.MatchString(component) {
+			return ImageReference{}, fmt.Errorf("invalid image reference %q: malformed repository", ref)
 		}
 	}
 
-	// Determine registry based on number of parts
-	if len(parts) >= 2 {
-		registry = parts[0]
+	// Official Docker Hub images live under the library namespace
+	if parsed.Registry == DefaultRegistry && !strings.Contains(parsed.Repository, "/") {
+		parsed.Repository = officialRepoPrefix + parsed.Repository
+	}
+
+	if parsed.Tag == "" && parsed.Digest == "" {
+		parsed.Tag = DefaultTag
 	}
 
-	return registry, tag
+	return parsed, nil
+}
+
+// NormalizeImageRef expands an image reference to its fully qualified form so
+// that equivalent references compare equal, e.g. "nginx" becomes
+// "docker.io/library/nginx:latest". Digest-pinned references keep their digest.
+// References that cannot be parsed are returned unchanged.
+func NormalizeImageRef(ref string) string {
+	parsed, err := ParseImageReference(ref)
+	if err != nil {
+		return ref
+	}
+	return parsed.String()
+}
+
+// ExtractDigest returns the "algorithm:hex" digest from an image reference or a
+// container status imageID such as "docker-pullable://nginx@sha256:...".
+// Returns an empty string if no digest is present.
+func ExtractDigest(ref string) string {
+	if i := strings.Index(ref, "://"); i >= 0 {
+		ref = ref[i+3:]
+	}
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		return ref[i+1:]
+	}
+	if strings.HasPrefix(ref, "sha256:") {
+		return ref
+	}
+	return ""
+}
+
+// IsUntagged reports whether an image reference has neither a tag nor a digest,
+// so that it resolves to the default tag, e.g. "nginx" or "localhost:5000/app".
+func IsUntagged(ref string) bool {
+	if strings.Contains(ref, "@") {
+		return false
+	}
+	return strings.LastIndex(ref, ":") < strings.LastIndex(ref, "/")+1
 }
@@ -1,97 +1,172 @@
 package util
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func TestExtractRegistryAndTag(t *testing.T) {
+func TestParseImageReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
 	tests := []struct {
-		name             string
-		imageName        string
-		expectedRegistry string
-		expectedTag      string
+		name      string
+		ref       string
+		expected  ImageReference
+		expectErr bool
 	}{
 		{
-			name:             "docker.io with tag",
-			imageName:        "docker.io/library/nginx:1.21",
-			expectedRegistry: "docker.io",
-			expectedTag:      "1.21",
+			name:     "docker.io with tag",
+			ref:      "docker.io/library/nginx:1.21",
+			expected: ImageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.21"},
+		},
+		{
+			name:     "gcr.io with version tag",
+			ref:      "gcr.io/project/image:v1.0",
+			expected: ImageReference{Registry: "gcr.io", Repository: "project/image", Tag: "v1.0"},
+		},
+		{
+			name:     "docker hub short name",
+			ref:      "ubuntu",
+			expected: ImageReference{Registry: "docker.io", Repository: "library/ubuntu", Tag: "latest"},
 		},
 		{
-			name:             "gcr.io with version tag",
-			imageName:        "gcr.io/project/image:v1.0",
-			expectedRegistry: "gcr.io",
-			expectedTag:      "v1.0",
+			name:     "docker hub short name with tag",
+			ref:      "nginx:1.21",
+			expected: ImageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.21"},
 		},
 		{
-			name:             "quay.io with latest tag",
-			imageName:        "quay.io/organization/app:latest",
-			expectedRegistry: "quay.io",
-			expectedTag:      "latest",
+			name:     "docker hub user repository",
+			ref:      "bitnami/redis:7.0",
+			expected: ImageReference{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.0"},
 		},
 		{
-			name:             "docker.io without tag",
-			imageName:        "docker.io/library/nginx",
-			expectedRegistry: "docker.io",
-			expectedTag:      "latest",
+			name:     "legacy docker hub host",
+			ref:      "index.docker.io/nginx",
+			expected: ImageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"},
 		},
 		{
-			name:             "single component no registry",
-			imageName:        "nginx",
-			expectedRegistry: "docker.io",
-			expectedTag:      "latest",
+			name:     "localhost with port",
+			ref:      "localhost:5000/app:v1",
+			expected: ImageReference{Registry: "localhost:5000", Repository: "app", Tag: "v1"},
 		},
 		{
-			name:             "single component with tag",
-			imageName:        "nginx:1.21",
-			expectedRegistry: "docker.io",
-			expectedTag:      "1.21",
+			name:     "registry with port and no tag",
+			ref:      "registry.company.com:8443/team/app",
+			expected: ImageReference{Registry: "registry.company.com:8443", Repository: "team/app", Tag: "latest"},
 		},
 		{
-			name:             "empty string",
-			imageName:        "",
-			expectedRegistry: "docker.io",
-			expectedTag:      "latest",
+			name:     "registry with port and digest only",
+			ref:      "registry:443/team/app@" + digest,
+			expected: ImageReference{Registry: "registry:443", Repository: "team/app", Digest: digest},
 		},
 		{
-			name:             "custom registry with port",
-			imageName:        "registry.company.com/team/app:v2",
-			expectedRegistry: "registry.company.com",
-			expectedTag:      "v2",
+			name:     "tag and digest",
+			ref:      "quay.io/org/app:v2@" + digest,
+			expected: ImageReference{Registry: "quay.io", Repository: "org/app", Tag: "v2", Digest: digest},
 		},
 		{
-			name:             "nested path",
-			imageName:        "gcr.io/proj/team/service:1.0",
-			expectedRegistry: "gcr.io",
-			expectedTag:      "1.0",
+			name:     "bare digest",
+			ref:      digest,
+			expected: ImageReference{Digest: digest},
 		},
 		{
-			name:             "special characters in tag",
-			imageName:        "docker.io/app:v1.0-alpha",
-			expectedRegistry: "docker.io",
-			expectedTag:      "v1.0-alpha",
+			name:     "tag that looks like a digest",
+			ref:      "app:" + strings.Repeat("b", 40),
+			expected: ImageReference{Registry: "docker.io", Repository: "library/app", Tag: strings.Repeat("b", 40)},
 		},
 		{
-			name:             "localhost with port",
-			imageName:        "localhost:5000/app:dev",
-			expectedRegistry: "localhost:5000",
-			expectedTag:      "dev",
+			name:     "nested path",
+			ref:      "gcr.io/proj/team/service:1.0",
+			expected: ImageReference{Registry: "gcr.io", Repository: "proj/team/service", Tag: "1.0"},
 		},
 		{
-			name:             "digest instead of tag",
-			imageName:        "docker.io/nginx@sha256:abc123",
-			expectedRegistry: "docker.io",
-			expectedTag:      "abc123",
+			name:     "special characters in tag",
+			ref:      "docker.io/app:v1.0-alpha",
+			expected: ImageReference{Registry: "docker.io", Repository: "library/app", Tag: "v1.0-alpha"},
 		},
+		{name: "empty string", ref: "", expectErr: true},
+		{name: "uppercase repository", ref: "docker.io/Library/nginx", expectErr: true},
+		{name: "malformed digest", ref: "nginx@sha256:abc123", expectErr: true},
+		{name: "malformed tag", ref: "nginx:-bad", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseImageReference(tt.ref)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ref)
+		})
+	}
+}
+
+func TestNormalizeImageRef(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := []struct {
+		name     string
+		ref      string
+		expected string
+	}{
+		{name: "short name", ref: "nginx", expected: "docker.io/library/nginx:latest"},
+		{name: "short name with tag", ref: "nginx:1.21", expected: "docker.io/library/nginx:1.21"},
+		{name: "docker hub user repo", ref: "bitnami/redis:7", expected: "docker.io/bitnami/redis:7"},
+		{name: "already normalized", ref: "docker.io/library/nginx:latest", expected: "docker.io/library/nginx:latest"},
+		{name: "legacy docker hub host", ref: "index.docker.io/library/nginx:1.21", expected: "docker.io/library/nginx:1.21"},
+		{name: "registry with port", ref: "localhost:5000/app", expected: "localhost:5000/app:latest"},
+		{name: "digest pinned", ref: "nginx@" + digest, expected: "docker.io/library/nginx@" + digest},
+		{name: "bare digest unchanged", ref: digest, expected: digest},
+		{name: "unparseable reference unchanged", ref: "Not A Ref", expected: "Not A Ref"},
+		{name: "empty string", ref: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeImageRef(tt.ref))
+		})
+	}
+}
+
+func TestIsUntagged(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected bool
+	}{
+		{ref: "nginx", expected: true},
+		{ref: "localhost:5000/app", expected: true},
+		{ref: "nginx:latest", expected: false},
+		{ref: "localhost:5000/app:v1", expected: false},
+		{ref: "nginx@sha256:abc123", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsUntagged(tt.ref))
+		})
+	}
+}
+
+func TestExtractDigest(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		expected string
+	}{
+		{name: "docker-pullable imageID", ref: "docker-pullable://nginx@sha256:abc123", expected: "sha256:abc123"},
+		{name: "containerd repo digest", ref: "docker.io/library/nginx@sha256:abc123", expected: "sha256:abc123"},
+		{name: "bare image ID", ref: "sha256:abc123", expected: "sha256:abc123"},
+		{name: "tag only", ref: "nginx:1.21", expected: ""},
+		{name: "empty string", ref: "", expected: ""},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			registry, tag := ExtractRegistryAndTag(tt.imageName)
-			assert.Equal(t, tt.expectedRegistry, registry)
-			assert.Equal(t, tt.expectedTag, tag)
+			assert.Equal(t, tt.expected, ExtractDigest(tt.ref))
 		})
 	}
 }